@@ -0,0 +1,184 @@
+package appres
+
+import (
+	"context"
+	"fmt"
+)
+
+// Apply creates every resource described in the schema.
+// Each database is applied in phases, so collections can refer to each other by name
+// regardless of the order they appear in:
+//
+//  1. The database is created with CreateDatabaseWithID and every collection with
+//     EnsureCollection, which gives them the IDs set in the schema.
+//  2. The attributes of every collection are applied with EnsureAttribute, leaving
+//     relationship attributes until last. By then every related collection exists, so
+//     relationships can name it with RelatedCollection instead of an ID known in advance.
+//  3. The indexes of every collection are applied with EnsureIndex once the collection's
+//     attributes are available.
+//
+// Appwrite cannot update indexes, so Apply stops with an *IndexDriftError at an existing
+// index that differs from the schema. Use ApplyWithOptions with RecreateIndexes set to delete
+// and create such indexes again instead.
+//
+// Buckets are applied with EnsureBucket after the databases. Existing resources are matched
+// the same way the individual functions match them, so applying the same schema again leaves
+// existing resources untouched, and collections, attributes and buckets whose settings have
+// changed in the schema are updated in place.
+//
+// The schema is checked with Schema.Validate before any request is made, which also rejects
+// two-way relationships declared on both sides. After that, Apply stops at the first error
+// and reports which resource it was working on.
+//
+// Parameters:
+//   - schema: The schema to apply, typically loaded with LoadSchema
+//
+// Returns:
+//   - error: Any error that occurred during the operation, or nil if successful
+//
+// Example:
+//
+//	schema, err := appres.LoadSchema("schema.yaml")
+//	if err != nil {
+//		log.Fatal("Failed to load schema:", err)
+//	}
+//	if err := client.Apply(schema); err != nil {
+//		log.Fatal("Failed to apply schema:", err)
+//	}
+func (c *Client) Apply(schema *Schema) error {
+	return c.ApplyWithOptions(schema, ApplyOptions{})
+}
+
+// Apply applies a schema using the default client initialised by Utils().
+// See Client.Apply for details.
+func Apply(schema *Schema) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.Apply(schema)
+}
+
+// ApplyContext is like Apply but sends every request with ctx and stops waiting for attributes
+// and indexes when ctx is done. Resources created before ctx ends are kept; applying the schema
+// again continues where it stopped.
+func (c *Client) ApplyContext(ctx context.Context, schema *Schema) error {
+	return c.withContext(ctx).Apply(schema)
+}
+
+// ApplyContext is like Apply but honours ctx, using the default client initialised by Utils().
+// See Client.ApplyContext for details.
+func ApplyContext(ctx context.Context, schema *Schema) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.ApplyContext(ctx, schema)
+}
+
+// ApplyOptions changes how ApplyWithOptions and PlanWithOptions treat existing resources.
+// The zero value behaves like Apply and Plan.
+type ApplyOptions struct {
+	// RecreateIndexes deletes and creates again every existing index that differs from the
+	// schema, as RecreateIndex does. The index is unavailable until Appwrite has rebuilt it,
+	// so by default such an index is reported as an *IndexDriftError instead.
+	RecreateIndexes bool
+}
+
+// ApplyWithOptions is like Apply, with opts changing how existing resources are treated.
+//
+// Parameters:
+//   - schema: The schema to apply, typically loaded with LoadSchema
+//   - opts: ApplyOptions struct, e.g. with RecreateIndexes set to replace drifted indexes
+//
+// Returns:
+//   - error: Any error that occurred during the operation, or nil if successful
+//
+// Example:
+//
+//	err := client.ApplyWithOptions(schema, appres.ApplyOptions{RecreateIndexes: true})
+//	if err != nil {
+//		log.Fatal("Failed to apply schema:", err)
+//	}
+func (c *Client) ApplyWithOptions(schema *Schema, opts ApplyOptions) error {
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+	for _, dbDef := range schema.Databases {
+		if err := c.applyDatabase(dbDef, opts); err != nil {
+			return err
+		}
+	}
+	for _, buc := range schema.Buckets {
+		if _, err := c.EnsureBucket(buc); err != nil {
+			return fmt.Errorf("bucket %q: %w", buc.Name, err)
+		}
+	}
+	return nil
+}
+
+// ApplyWithOptions applies a schema using the default client initialised by Utils().
+// See Client.ApplyWithOptions for details.
+func ApplyWithOptions(schema *Schema, opts ApplyOptions) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.ApplyWithOptions(schema, opts)
+}
+
+// ApplyWithOptionsContext is like ApplyWithOptions but sends every request with ctx, as
+// ApplyContext does.
+func (c *Client) ApplyWithOptionsContext(ctx context.Context, schema *Schema, opts ApplyOptions) error {
+	return c.withContext(ctx).ApplyWithOptions(schema, opts)
+}
+
+// ApplyWithOptionsContext is like ApplyWithOptions but honours ctx, using the default client initialised by Utils().
+// See Client.ApplyWithOptionsContext for details.
+func ApplyWithOptionsContext(ctx context.Context, schema *Schema, opts ApplyOptions) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.ApplyWithOptionsContext(ctx, schema, opts)
+}
+
+// applyDatabase applies a single database of a schema in the phases described on Apply.
+func (c *Client) applyDatabase(dbDef DatabaseType, opts ApplyOptions) error {
+	db, err := c.CreateDatabaseWithID(dbDef.ID, dbDef.Name)
+	if err != nil {
+		return fmt.Errorf("database %q: %w", dbDef.Name, err)
+	}
+	colIDs := make([]string, len(dbDef.Collections))
+	for i, colDef := range dbDef.Collections {
+		col, err := c.EnsureCollection(db.Id, colDef)
+		if err != nil {
+			return fmt.Errorf("database %q: collection %q: %w", dbDef.Name, colDef.Name, err)
+		}
+		colIDs[i] = col.Id
+	}
+	for _, relationships := range []bool{false, true} {
+		for i, colDef := range dbDef.Collections {
+			for _, att := range colDef.Attributes {
+				if (att.Type == "relationship") != relationships {
+					continue
+				}
+				if err := c.EnsureAttribute(db.Id, colIDs[i], att); err != nil {
+					return fmt.Errorf("database %q: collection %q: attribute %q: %w", dbDef.Name, colDef.Name, att.key(), err)
+				}
+			}
+		}
+	}
+	for i, colDef := range dbDef.Collections {
+		for _, idx := range colDef.Indexes {
+			ensure := c.EnsureIndex
+			if opts.RecreateIndexes {
+				ensure = c.RecreateIndex
+			}
+			if err := ensure(db.Id, colIDs[i], idx); err != nil {
+				return fmt.Errorf("database %q: collection %q: index %q: %w", dbDef.Name, colDef.Name, idx.Key, err)
+			}
+		}
+	}
+	return nil
+}
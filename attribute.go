@@ -1,8 +1,9 @@
 package appres
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/appwrite/sdk-for-go/databases"
@@ -11,18 +12,28 @@ import (
 // CreateAttribute creates a new attribute in the specified collection or skips creation if it already exists.
 // It checks for duplicates to avoid errors and supports all major attribute types.
 //
+// When an attribute with the same key already exists, its configuration is compared with att
+// using DiffAttribute. A matching attribute is left untouched and nil is returned; a mismatch
+// is reported as an *AttributeDriftError listing every differing field.
+//
+// The definition is checked with AttributeType.Validate before any request is made, so all
+// problems with it are reported together.
+//
+// Relationship attributes are matched by their effective key: Name, or the related collection
+// ID when Name is empty. The related collection may be given by ID or by name and must exist in
+// the same database. For two-way relationships the key added to the related collection
+// (TwoWayKey, or the ID of this collection by default) must not already be in use there.
+//
 // Parameters:
 //   - dbID: The ID of the database containing the collection
 //   - colID: The ID of the collection where the attribute should be created
-//   - att: AttributeType struct containing the attribute configuration
-//
-// Global Variables Used:
-//   - AppwriteDatabase: The initialized Appwrite database client
+//   - def: The attribute configuration, either an AttributeType or a typed definition such as StringAttr
 //
 // Returns:
-//   - error: Any error that occurred during the operation, or nil if successful
+//   - error: Any error that occurred during the operation, *AttributeDriftError if an existing
+//     attribute differs, or nil if successful
 //
-// Supported types: string, email, integer, datetime, boolean, relationship, url
+// Supported types: string, email, integer, float, datetime, boolean, relationship, url, enum, ip
 //
 // Example:
 //
@@ -35,39 +46,82 @@ import (
 //		Array:    false,
 //		Encrypt:  false,
 //	}
-//	err := app.CreateAttribute(db.Id, col.Id, attr)
+//	err := client.CreateAttribute(db.Id, col.Id, attr)
 //	if err != nil {
 //		log.Fatal("Failed to create attribute:", err)
 //	}
-func CreateAttribute(dbID string, colID string, att AttributeType) error {
-	attributes, err := AppwriteDatabase.ListAttributes(dbID, colID)
+//
+//	// The same attribute as a typed definition
+//	err = client.CreateAttribute(db.Id, col.Id, app.StringAttr{Name: "title", Size: 255, Required: true})
+func (c *Client) CreateAttribute(dbID string, colID string, def Attribute) error {
+	att := def.Definition()
+	if err := att.Validate(); err != nil {
+		return err
+	}
+	if att.Type == "relationship" {
+		resolved, err := c.resolveRelationship(dbID, colID, att)
+		if err != nil {
+			return err
+		}
+		att = resolved
+	}
+	if found, err := c.existingAttribute(dbID, colID, att); found || err != nil {
+		return err
+	}
+	start := time.Now()
+	err := c.createAttribute(dbID, colID, att)
+	if isConflict(err) {
+		return c.recoverConflict("attribute", att.Name, err, func() (bool, error) {
+			return c.existingAttribute(dbID, colID, att)
+		}, "collection", colID)
+	}
 	if err != nil {
-		log.Println("Error listing attributes:", err)
+		c.logger.ErrorContext(c.context(), "Error creating attribute",
+			"kind", "attribute", "name", att.Name, "collection", colID, "action", "create", "error", err)
 		return err
 	}
-	for _, attr := range attributes.Attributes {
+	c.logger.InfoContext(c.context(), "Attribute created",
+		"kind", "attribute", "name", att.Name, "collection", colID, "action", "create", "duration", time.Since(start))
+	return nil
+}
+
+// existingAttribute looks the attribute up in the collection. It reports whether the
+// attribute exists, with an *AttributeDriftError if it differs from att.
+func (c *Client) existingAttribute(dbID string, colID string, att AttributeType) (bool, error) {
+	attributes, err := c.listAttributes(dbID, colID)
+	if err != nil {
+		return false, err
+	}
+	for _, attr := range attributes {
 		if attrName, ok := attr["key"].(string); ok && attrName == att.Name {
-			log.Println("Attribute already exists with key:", attr["key"])
-			return nil
+			if drift := DiffAttribute(attr, att); len(drift) > 0 {
+				err := &AttributeDriftError{Key: att.Name, Fields: drift}
+				c.logger.DebugContext(c.context(), "Attribute already exists but differs",
+					"kind", "attribute", "name", att.Name, "collection", colID, "action", "skip", "error", err)
+				return true, err
+			}
+			c.logger.DebugContext(c.context(), "Attribute already exists",
+				"kind", "attribute", "name", att.Name, "collection", colID, "action", "skip")
+			return true, nil
 		}
 	}
+	return false, nil
+}
+
+// createAttribute creates the validated and resolved attribute with the call for its type.
+func (c *Client) createAttribute(dbID string, colID string, att AttributeType) error {
 	//----------------------------------------------------------------------------------------
 	// Create STRING attribute
 	//----------------------------------------------------------------------------------------
 	if att.Type == "string" {
-		if att.Default != nil {
-			if _, ok := att.Default.(string); !ok {
-				return fmt.Errorf("default value for string attribute must be a string")
-			}
-		}
 		var opts []databases.CreateStringAttributeOption
 		if !att.Required && att.Default != nil {
-			opts = append(opts, AppwriteDatabase.WithCreateStringAttributeDefault(att.Default.(string)))
+			opts = append(opts, c.databases.WithCreateStringAttributeDefault(att.Default.(string)))
 		}
-		opts = append(opts, AppwriteDatabase.WithCreateStringAttributeArray(att.Array))
-		opts = append(opts, AppwriteDatabase.WithCreateStringAttributeEncrypt(att.Encrypt))
+		opts = append(opts, c.databases.WithCreateStringAttributeArray(att.Array))
+		opts = append(opts, c.databases.WithCreateStringAttributeEncrypt(att.Encrypt))
 
-		newAtt, err := AppwriteDatabase.CreateStringAttribute(
+		_, err := c.databases.CreateStringAttribute(
 			dbID,
 			colID,
 			att.Name,
@@ -75,197 +129,419 @@ func CreateAttribute(dbID string, colID string, att AttributeType) error {
 			att.Required,
 			opts...,
 		)
-		if err != nil {
-			log.Println("error creating attribute:", err)
-			return err
-		}
-		log.Println("attribute created with key:", newAtt.Key)
-		return nil
+		return err
 		//----------------------------------------------------------------------------------------
 		// Create EMAIL attribute
 		//----------------------------------------------------------------------------------------
 	} else if att.Type == "email" {
-		if att.Default != nil {
-			if _, ok := att.Default.(string); !ok {
-				return fmt.Errorf("default value for string attribute must be a string")
-			}
-		}
 		var opts []databases.CreateEmailAttributeOption
 		if !att.Required && att.Default != nil {
-			opts = append(opts, AppwriteDatabase.WithCreateEmailAttributeDefault(att.Default.(string)))
+			opts = append(opts, c.databases.WithCreateEmailAttributeDefault(att.Default.(string)))
 		}
-		opts = append(opts, AppwriteDatabase.WithCreateEmailAttributeArray(att.Array))
-		newAtt, err := AppwriteDatabase.CreateEmailAttribute(
+		opts = append(opts, c.databases.WithCreateEmailAttributeArray(att.Array))
+		_, err := c.databases.CreateEmailAttribute(
 			dbID,
 			colID,
 			att.Name,
 			att.Required,
 			opts...,
 		)
-		if err != nil {
-			log.Println("error creating attribute:", err)
-			return err
-		}
-		log.Println("attribute created with key:", newAtt.Key)
-		return nil
+		return err
 		//----------------------------------------------------------------------------------------
 		// Create INTEGER attribute
 		//----------------------------------------------------------------------------------------
 	} else if att.Type == "integer" {
-		if att.Default != nil {
-			_, ok := att.Default.(int)
-			if !ok {
-				return fmt.Errorf("default value for integer attribute must be an int")
-			}
-		}
-		if att.Min != nil {
-			_, ok := att.Min.(int)
-			if !ok {
-				return fmt.Errorf("min value for integer attribute must be an int")
-			}
-		}
-		if att.Max != nil {
-			_, ok := att.Max.(int)
-			if !ok {
-				return fmt.Errorf("max value for integer attribute must be an int")
-			}
-		}
 		var opts []databases.CreateIntegerAttributeOption
 		if !att.Required && att.Default != nil {
-			opts = append(opts, AppwriteDatabase.WithCreateIntegerAttributeDefault(att.Default.(int)))
+			opts = append(opts, c.databases.WithCreateIntegerAttributeDefault(att.Default.(int)))
 		}
 		if att.Min != nil {
-			opts = append(opts, AppwriteDatabase.WithCreateIntegerAttributeMin(att.Min.(int)))
+			opts = append(opts, c.databases.WithCreateIntegerAttributeMin(att.Min.(int)))
 		}
 		if att.Max != nil {
-			opts = append(opts, AppwriteDatabase.WithCreateIntegerAttributeMax(att.Max.(int)))
+			opts = append(opts, c.databases.WithCreateIntegerAttributeMax(att.Max.(int)))
 		}
-		opts = append(opts, AppwriteDatabase.WithCreateIntegerAttributeArray(att.Array))
-		newAtt, err := AppwriteDatabase.CreateIntegerAttribute(
+		opts = append(opts, c.databases.WithCreateIntegerAttributeArray(att.Array))
+		_, err := c.databases.CreateIntegerAttribute(
 			dbID,
 			colID,
 			att.Name,
 			att.Required,
 			opts...,
 		)
-		if err != nil {
-			log.Println("error creating attribute:", err)
-			return err
-		}
-		log.Println("attribute created with key:", newAtt.Key)
-		return nil
+		return err
 		//----------------------------------------------------------------------------------------
 		// Create DATETIME attribute
 		//----------------------------------------------------------------------------------------
 	} else if att.Type == "datetime" {
-		layout := time.RFC3339
-		if att.Default != nil {
-			s, ok := att.Default.(string)
-			if !ok {
-				return fmt.Errorf("default value for datetime attribute must be a string")
-			}
-			if _, err := time.Parse(layout, s); err != nil {
-				return fmt.Errorf("default value for datetime attribute must be a valid RFC3339 datetime string: %v", err)
-			}
-		}
 		var opts []databases.CreateDatetimeAttributeOption
 		if !att.Required && att.Default != nil {
-			opts = append(opts, AppwriteDatabase.WithCreateDatetimeAttributeDefault(att.Default.(string)))
+			opts = append(opts, c.databases.WithCreateDatetimeAttributeDefault(att.Default.(string)))
 		}
-		opts = append(opts, AppwriteDatabase.WithCreateDatetimeAttributeArray(att.Array))
-		newAtt, err := AppwriteDatabase.CreateDatetimeAttribute(
+		opts = append(opts, c.databases.WithCreateDatetimeAttributeArray(att.Array))
+		_, err := c.databases.CreateDatetimeAttribute(
 			dbID,
 			colID,
 			att.Name,
 			att.Required,
 			opts...,
 		)
-		if err != nil {
-			log.Println("error creating attribute:", err)
-			return err
-		}
-		log.Println("attribute created with key:", newAtt.Key)
-		return nil
+		return err
 		//----------------------------------------------------------------------------------------
 		// Create BOOLEAN attribute
 		//----------------------------------------------------------------------------------------
 	} else if att.Type == "boolean" {
-		if att.Default != nil {
-			if _, ok := att.Default.(bool); !ok {
-				return fmt.Errorf("default value for boolean attribute must be a bool")
-			}
-		}
 		var opts []databases.CreateBooleanAttributeOption
 		if !att.Required && att.Default != nil {
-			opts = append(opts, AppwriteDatabase.WithCreateBooleanAttributeDefault(att.Default.(bool)))
+			opts = append(opts, c.databases.WithCreateBooleanAttributeDefault(att.Default.(bool)))
 		}
-		opts = append(opts, AppwriteDatabase.WithCreateBooleanAttributeArray(att.Array))
-		newAtt, err := AppwriteDatabase.CreateBooleanAttribute(
+		opts = append(opts, c.databases.WithCreateBooleanAttributeArray(att.Array))
+		_, err := c.databases.CreateBooleanAttribute(
 			dbID,
 			colID,
 			att.Name,
 			att.Required,
 			opts...,
 		)
-		if err != nil {
-			log.Println("error creating attribute:", err)
-			return err
-		}
-		log.Println("attribute created with key:", newAtt.Key)
-		return nil
+		return err
 		//----------------------------------------------------------------------------------------
 		// Create RELATIONSHIP attribute
 		//----------------------------------------------------------------------------------------
 	} else if att.Type == "relationship" {
+		if att.TwoWay {
+			if err := c.checkTwoWayKey(dbID, att); err != nil {
+				return err
+			}
+		}
 		var opts []databases.CreateRelationshipAttributeOption
-		opts = append(opts, AppwriteDatabase.WithCreateRelationshipAttributeTwoWay(att.TwoWay))
+		opts = append(opts, c.databases.WithCreateRelationshipAttributeTwoWay(att.TwoWay))
 		if att.Name != "" {
-			opts = append(opts, AppwriteDatabase.WithCreateRelationshipAttributeKey(att.Name))
+			opts = append(opts, c.databases.WithCreateRelationshipAttributeKey(att.Name))
 		}
 		if att.OnDelete != "" {
-			opts = append(opts, AppwriteDatabase.WithCreateRelationshipAttributeOnDelete(att.OnDelete))
+			opts = append(opts, c.databases.WithCreateRelationshipAttributeOnDelete(att.OnDelete))
 		}
 		if att.TwoWayKey != "" {
-			opts = append(opts, AppwriteDatabase.WithCreateRelationshipAttributeTwoWayKey(att.TwoWayKey))
+			opts = append(opts, c.databases.WithCreateRelationshipAttributeTwoWayKey(att.TwoWayKey))
 		}
-		newAtt, err := AppwriteDatabase.CreateRelationshipAttribute(
+		_, err := c.databases.CreateRelationshipAttribute(
 			dbID,
 			colID,
 			att.RelatedCollectionID,
 			att.RelationshipType,
 			opts...,
 		)
-		if err != nil {
-			log.Println("error creating attribute:", err)
-			return err
-		}
-		log.Println("attribute created with key:", newAtt.Key)
-		return nil
+		return err
 		//----------------------------------------------------------------------------------------
 		// Create URL attribute
 		//----------------------------------------------------------------------------------------
 	} else if att.Type == "url" {
 		var opts []databases.CreateUrlAttributeOption
-		if att.Default != nil {
-			if _, ok := att.Default.(string); !ok {
-				return fmt.Errorf("default value for url attribute must be a string")
-			}
-			opts = append(opts, AppwriteDatabase.WithCreateUrlAttributeDefault(att.Default.(string)))
+		if !att.Required && att.Default != nil {
+			opts = append(opts, c.databases.WithCreateUrlAttributeDefault(att.Default.(string)))
+		}
+		opts = append(opts, c.databases.WithCreateUrlAttributeArray(att.Array))
+		_, err := c.databases.CreateUrlAttribute(
+			dbID,
+			colID,
+			att.Name,
+			att.Required,
+			opts...,
+		)
+		return err
+		//----------------------------------------------------------------------------------------
+		// Create FLOAT attribute
+		//----------------------------------------------------------------------------------------
+	} else if att.Type == "float" {
+		var opts []databases.CreateFloatAttributeOption
+		if !att.Required && att.Default != nil {
+			def, _ := toFloat(att.Default)
+			opts = append(opts, c.databases.WithCreateFloatAttributeDefault(def))
+		}
+		if att.Min != nil {
+			min, _ := toFloat(att.Min)
+			opts = append(opts, c.databases.WithCreateFloatAttributeMin(min))
+		}
+		if att.Max != nil {
+			max, _ := toFloat(att.Max)
+			opts = append(opts, c.databases.WithCreateFloatAttributeMax(max))
+		}
+		opts = append(opts, c.databases.WithCreateFloatAttributeArray(att.Array))
+		_, err := c.databases.CreateFloatAttribute(
+			dbID,
+			colID,
+			att.Name,
+			att.Required,
+			opts...,
+		)
+		return err
+		//----------------------------------------------------------------------------------------
+		// Create ENUM attribute
+		//----------------------------------------------------------------------------------------
+	} else if att.Type == "enum" {
+		var opts []databases.CreateEnumAttributeOption
+		if !att.Required && att.Default != nil {
+			opts = append(opts, c.databases.WithCreateEnumAttributeDefault(att.Default.(string)))
+		}
+		opts = append(opts, c.databases.WithCreateEnumAttributeArray(att.Array))
+		_, err := c.databases.CreateEnumAttribute(
+			dbID,
+			colID,
+			att.Name,
+			att.Elements,
+			att.Required,
+			opts...,
+		)
+		return err
+		//----------------------------------------------------------------------------------------
+		// Create IP attribute
+		//----------------------------------------------------------------------------------------
+	} else if att.Type == "ip" {
+		var opts []databases.CreateIpAttributeOption
+		if !att.Required && att.Default != nil {
+			opts = append(opts, c.databases.WithCreateIpAttributeDefault(att.Default.(string)))
 		}
-		opts = append(opts, AppwriteDatabase.WithCreateUrlAttributeArray(att.Array))
-		newAtt, err := AppwriteDatabase.CreateUrlAttribute(
+		opts = append(opts, c.databases.WithCreateIpAttributeArray(att.Array))
+		_, err := c.databases.CreateIpAttribute(
 			dbID,
 			colID,
 			att.Name,
 			att.Required,
 			opts...,
 		)
+		return err
+	}
+	return fmt.Errorf("unsupported attribute type: %s", att.Type)
+}
+
+// CreateAttribute creates an attribute using the default client initialised by Utils().
+// See Client.CreateAttribute for details.
+func CreateAttribute(dbID string, colID string, def Attribute) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.CreateAttribute(dbID, colID, def)
+}
+
+// CreateAttributeContext is like CreateAttribute but sends its requests with ctx.
+func (c *Client) CreateAttributeContext(ctx context.Context, dbID string, colID string, def Attribute) error {
+	return c.withContext(ctx).CreateAttribute(dbID, colID, def)
+}
+
+// CreateAttributeContext is like CreateAttribute but honours ctx, using the default client initialised by Utils().
+// See Client.CreateAttributeContext for details.
+func CreateAttributeContext(ctx context.Context, dbID string, colID string, def Attribute) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.CreateAttributeContext(ctx, dbID, colID, def)
+}
+
+// UpdateAttribute brings an existing attribute in line with the AttributeType.
+// It compares the live attribute with att and, if they differ, calls the Appwrite update
+// endpoint for the attribute type to change Required, Default, Size (string),
+// Min/Max (integer and float) and Elements (enum). For relationship attributes only
+// OnDelete can be updated. Min, Max and Default keep their current value when nil, except
+// that a required attribute never has a default.
+//
+// Type, Array, Encrypt and the relationship target, type and direction cannot be changed
+// once an attribute exists. If any of those differ, no update is made and an error wrapping
+// an *AttributeDriftError with the offending fields is returned.
+//
+// Parameters:
+//   - dbID: The ID of the database containing the collection
+//   - colID: The ID of the collection containing the attribute
+//   - def: The desired attribute configuration, either an AttributeType or a typed definition such as StringAttr
+//
+// Returns:
+//   - error: Any error that occurred during the operation, or nil if successful
+//
+// Example:
+//
+//	attr := app.AttributeType{
+//		Type:     "string",
+//		Name:     "title",
+//		Size:     255,
+//		Required: true,
+//	}
+//	err := client.UpdateAttribute(db.Id, col.Id, attr)
+//	if err != nil {
+//		log.Fatal("Failed to update attribute:", err)
+//	}
+func (c *Client) UpdateAttribute(dbID string, colID string, def Attribute) error {
+	att := def.Definition()
+	if err := att.Validate(); err != nil {
+		return err
+	}
+	if att.Type == "relationship" {
+		resolved, err := c.resolveRelationship(dbID, colID, att)
 		if err != nil {
-			log.Println("error creating attribute:", err)
 			return err
 		}
-		log.Println("attribute created with key:", newAtt.Key)
+		att = resolved
+	}
+	attributes, err := c.listAttributes(dbID, colID)
+	if err != nil {
+		return err
+	}
+	var live map[string]interface{}
+	for _, attr := range attributes {
+		if attrName, ok := attr["key"].(string); ok && attrName == att.Name {
+			live = attr
+			break
+		}
+	}
+	if live == nil {
+		return fmt.Errorf("attribute %q not found", att.Name)
+	}
+	drift := DiffAttribute(live, att)
+	if len(drift) == 0 {
+		c.logger.DebugContext(c.context(), "Attribute already up to date",
+			"kind", "attribute", "name", att.Name, "collection", colID, "action", "skip")
 		return nil
 	}
-	return fmt.Errorf("unsupported attribute type: %s", att.Type)
-}
\ No newline at end of file
+	if fixed := immutableDrift(drift); len(fixed) > 0 {
+		return fmt.Errorf("attribute cannot be updated in place: %w", &AttributeDriftError{Key: att.Name, Fields: fixed})
+	}
+	start := time.Now()
+
+	if att.Type == "relationship" {
+		_, err := c.databases.UpdateRelationshipAttribute(
+			dbID,
+			colID,
+			att.Name,
+			c.databases.WithUpdateRelationshipAttributeOnDelete(att.OnDelete),
+		)
+		if err != nil {
+			c.logUpdateAttributeError(colID, att, err)
+			return err
+		}
+		c.logUpdateAttribute(colID, att, start)
+		return nil
+	}
+
+	// The SDK update wrappers always send a default value, which Appwrite rejects for
+	// required attributes and which cannot clear an existing default. The endpoints are
+	// called directly so that the default can be sent as null.
+	params := map[string]interface{}{
+		"required": att.Required,
+		"default":  nil,
+	}
+	switch {
+	case att.Required:
+	case att.Default == nil:
+		params["default"] = live["default"]
+	case att.Default != "":
+		params["default"] = att.Default
+	}
+	switch att.Type {
+	case "string":
+		params["size"] = att.Size
+	case "integer", "float":
+		if att.Min != nil {
+			params["min"] = att.Min
+		}
+		if att.Max != nil {
+			params["max"] = att.Max
+		}
+	case "enum":
+		params["elements"] = att.Elements
+	case "email", "datetime", "boolean", "url", "ip":
+	default:
+		return fmt.Errorf("unsupported attribute type: %s", att.Type)
+	}
+	path := "/databases/" + dbID + "/collections/" + colID + "/attributes/" + att.Type + "/" + att.Name
+	headers := map[string]interface{}{
+		"content-type": "application/json",
+	}
+	if _, err := c.appwrite.Call("PATCH", path, headers, params); err != nil {
+		c.logUpdateAttributeError(colID, att, err)
+		return err
+	}
+	c.logUpdateAttribute(colID, att, start)
+	return nil
+}
+
+// logUpdateAttribute logs a successful update of an attribute started at start.
+func (c *Client) logUpdateAttribute(colID string, att AttributeType, start time.Time) {
+	c.logger.InfoContext(c.context(), "Attribute updated",
+		"kind", "attribute", "name", att.Name, "collection", colID, "action", "update", "duration", time.Since(start))
+}
+
+// logUpdateAttributeError logs a failed update of an attribute.
+func (c *Client) logUpdateAttributeError(colID string, att AttributeType, err error) {
+	c.logger.ErrorContext(c.context(), "Error updating attribute",
+		"kind", "attribute", "name", att.Name, "collection", colID, "action", "update", "error", err)
+}
+
+// UpdateAttribute updates an attribute using the default client initialised by Utils().
+// See Client.UpdateAttribute for details.
+func UpdateAttribute(dbID string, colID string, def Attribute) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.UpdateAttribute(dbID, colID, def)
+}
+
+// UpdateAttributeContext is like UpdateAttribute but sends its requests with ctx.
+func (c *Client) UpdateAttributeContext(ctx context.Context, dbID string, colID string, def Attribute) error {
+	return c.withContext(ctx).UpdateAttribute(dbID, colID, def)
+}
+
+// UpdateAttributeContext is like UpdateAttribute but honours ctx, using the default client initialised by Utils().
+// See Client.UpdateAttributeContext for details.
+func UpdateAttributeContext(ctx context.Context, dbID string, colID string, def Attribute) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.UpdateAttributeContext(ctx, dbID, colID, def)
+}
+
+// EnsureAttribute makes sure the attribute exists and matches the AttributeType.
+// Missing attributes are created with CreateAttribute and drifted attributes are
+// updated with UpdateAttribute, so a schema can evolve without manual changes.
+//
+// Parameters:
+//   - dbID: The ID of the database containing the collection
+//   - colID: The ID of the collection where the attribute should exist
+//   - def: The desired attribute configuration, either an AttributeType or a typed definition such as StringAttr
+//
+// Returns:
+//   - error: Any error that occurred during the operation, or nil if successful
+func (c *Client) EnsureAttribute(dbID string, colID string, def Attribute) error {
+	err := c.CreateAttribute(dbID, colID, def)
+	var drift *AttributeDriftError
+	if errors.As(err, &drift) {
+		return c.UpdateAttribute(dbID, colID, def)
+	}
+	return err
+}
+
+// EnsureAttribute creates or updates an attribute using the default client initialised by Utils().
+// See Client.EnsureAttribute for details.
+func EnsureAttribute(dbID string, colID string, def Attribute) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.EnsureAttribute(dbID, colID, def)
+}
+
+// EnsureAttributeContext is like EnsureAttribute but sends its requests with ctx.
+func (c *Client) EnsureAttributeContext(ctx context.Context, dbID string, colID string, def Attribute) error {
+	return c.withContext(ctx).EnsureAttribute(dbID, colID, def)
+}
+
+// EnsureAttributeContext is like EnsureAttribute but honours ctx, using the default client initialised by Utils().
+// See Client.EnsureAttributeContext for details.
+func EnsureAttributeContext(ctx context.Context, dbID string, colID string, def Attribute) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.EnsureAttributeContext(ctx, dbID, colID, def)
+}
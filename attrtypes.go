@@ -0,0 +1,321 @@
+package appres
+
+import "time"
+
+// Attribute is implemented by every attribute definition accepted by CreateAttribute,
+// UpdateAttribute and EnsureAttribute.
+//
+// The typed definitions below (StringAttr, IntegerAttr, ...) only have the fields that apply
+// to their attribute type, so settings such as Encrypt on an integer or Size on a boolean do
+// not compile, and defaults and bounds have the right Go type. AttributeType also implements
+// Attribute and remains available for definitions built at runtime, such as those loaded
+// from a schema file.
+//
+// Example:
+//
+//	err := client.CreateAttribute(db.Id, col.Id, appres.IntegerAttr{
+//		Name:    "age",
+//		Min:     appres.Ptr(0),
+//		Max:     appres.Ptr(120),
+//		Default: appres.Ptr(18),
+//	})
+type Attribute interface {
+	// Definition returns the attribute as an AttributeType
+	Definition() AttributeType
+}
+
+// Definition returns the AttributeType itself, so it can be used wherever an Attribute is expected.
+func (a AttributeType) Definition() AttributeType {
+	return a
+}
+
+// Ptr returns a pointer to v. It is a shorthand for setting the optional Default, Min and
+// Max fields of the typed attribute definitions.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// StringAttr defines a string attribute.
+type StringAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Size is the maximum length of the string
+	Size int
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *string
+
+	// Array indicates whether the attribute stores a list of strings
+	Array bool
+
+	// Encrypt determines whether the value is encrypted at rest
+	Encrypt bool
+}
+
+// Definition returns the attribute as an AttributeType of type "string".
+func (a StringAttr) Definition() AttributeType {
+	return AttributeType{
+		Type:     "string",
+		Name:     a.Name,
+		Size:     a.Size,
+		Required: a.Required,
+		Default:  optional(a.Default),
+		Array:    a.Array,
+		Encrypt:  a.Encrypt,
+	}
+}
+
+// EmailAttr defines an email attribute.
+type EmailAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *string
+
+	// Array indicates whether the attribute stores a list of email addresses
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "email".
+func (a EmailAttr) Definition() AttributeType {
+	return AttributeType{Type: "email", Name: a.Name, Required: a.Required, Default: optional(a.Default), Array: a.Array}
+}
+
+// URLAttr defines a URL attribute.
+type URLAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *string
+
+	// Array indicates whether the attribute stores a list of URLs
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "url".
+func (a URLAttr) Definition() AttributeType {
+	return AttributeType{Type: "url", Name: a.Name, Required: a.Required, Default: optional(a.Default), Array: a.Array}
+}
+
+// IPAttr defines an IP address attribute.
+type IPAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *string
+
+	// Array indicates whether the attribute stores a list of IP addresses
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "ip".
+func (a IPAttr) Definition() AttributeType {
+	return AttributeType{Type: "ip", Name: a.Name, Required: a.Required, Default: optional(a.Default), Array: a.Array}
+}
+
+// EnumAttr defines an enum attribute.
+type EnumAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Elements lists the allowed values
+	Elements []string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional), one of Elements
+	Default *string
+
+	// Array indicates whether the attribute stores a list of values
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "enum".
+func (a EnumAttr) Definition() AttributeType {
+	return AttributeType{
+		Type:     "enum",
+		Name:     a.Name,
+		Elements: a.Elements,
+		Required: a.Required,
+		Default:  optional(a.Default),
+		Array:    a.Array,
+	}
+}
+
+// IntegerAttr defines an integer attribute.
+type IntegerAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *int
+
+	// Min is the smallest allowed value (optional)
+	Min *int
+
+	// Max is the largest allowed value (optional)
+	Max *int
+
+	// Array indicates whether the attribute stores a list of integers
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "integer".
+func (a IntegerAttr) Definition() AttributeType {
+	return AttributeType{
+		Type:     "integer",
+		Name:     a.Name,
+		Required: a.Required,
+		Default:  optional(a.Default),
+		Min:      optional(a.Min),
+		Max:      optional(a.Max),
+		Array:    a.Array,
+	}
+}
+
+// FloatAttr defines a float attribute.
+type FloatAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *float64
+
+	// Min is the smallest allowed value (optional)
+	Min *float64
+
+	// Max is the largest allowed value (optional)
+	Max *float64
+
+	// Array indicates whether the attribute stores a list of numbers
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "float".
+func (a FloatAttr) Definition() AttributeType {
+	return AttributeType{
+		Type:     "float",
+		Name:     a.Name,
+		Required: a.Required,
+		Default:  optional(a.Default),
+		Min:      optional(a.Min),
+		Max:      optional(a.Max),
+		Array:    a.Array,
+	}
+}
+
+// BooleanAttr defines a boolean attribute.
+type BooleanAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *bool
+
+	// Array indicates whether the attribute stores a list of booleans
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "boolean".
+func (a BooleanAttr) Definition() AttributeType {
+	return AttributeType{Type: "boolean", Name: a.Name, Required: a.Required, Default: optional(a.Default), Array: a.Array}
+}
+
+// DatetimeAttr defines a datetime attribute.
+type DatetimeAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// Required determines whether this attribute must have a value
+	Required bool
+
+	// Default is the value used when none is provided (optional)
+	Default *time.Time
+
+	// Array indicates whether the attribute stores a list of datetimes
+	Array bool
+}
+
+// Definition returns the attribute as an AttributeType of type "datetime".
+// The default is formatted as an RFC3339 string.
+func (a DatetimeAttr) Definition() AttributeType {
+	att := AttributeType{Type: "datetime", Name: a.Name, Required: a.Required, Array: a.Array}
+	if a.Default != nil {
+		att.Default = a.Default.Format(time.RFC3339)
+	}
+	return att
+}
+
+// RelationshipAttr defines a relationship attribute linking two collections.
+type RelationshipAttr struct {
+	// Name is the key/identifier for the attribute in the collection
+	Name string
+
+	// RelatedCollectionID is the ID of the collection this attribute links to
+	RelatedCollectionID string
+
+	// RelatedCollection is the name of the collection this attribute links to, used when
+	// RelatedCollectionID is empty
+	RelatedCollection string
+
+	// RelationshipType must be one of `oneToOne`, `oneToMany`, `manyToOne`, `manyToMany`
+	RelationshipType string
+
+	// TwoWay makes the relationship visible from the related collection as well
+	TwoWay bool
+
+	// TwoWayKey is the key of the attribute on the related collection (optional)
+	TwoWayKey string
+
+	// OnDelete must be one of `restrict`, `cascade`, `setNull` (optional)
+	OnDelete string
+}
+
+// Definition returns the attribute as an AttributeType of type "relationship".
+func (a RelationshipAttr) Definition() AttributeType {
+	return AttributeType{
+		Type:                "relationship",
+		Name:                a.Name,
+		RelatedCollectionID: a.RelatedCollectionID,
+		RelatedCollection:   a.RelatedCollection,
+		RelationshipType:    a.RelationshipType,
+		TwoWay:              a.TwoWay,
+		TwoWayKey:           a.TwoWayKey,
+		OnDelete:            a.OnDelete,
+	}
+}
+
+// optional returns *v, or an untyped nil when v is nil, so unset typed values
+// stay unset in the AttributeType.
+func optional[T any](v *T) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
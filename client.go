@@ -0,0 +1,263 @@
+package appres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/appwrite/sdk-for-go/appwrite"
+	"github.com/appwrite/sdk-for-go/client"
+	"github.com/appwrite/sdk-for-go/databases"
+	"github.com/appwrite/sdk-for-go/storage"
+
+	"github.com/Haepapa/appres/helper"
+)
+
+// ErrNotInitialised is returned by the package-level functions when no default client has
+// been set up with Utils() or SetDefault().
+var ErrNotInitialised = errors.New("appres: default client not initialised, call Utils() first")
+
+// Client talks to a single Appwrite project. It carries its own database and storage
+// services, so several clients can be used side by side in one process, for example to
+// copy a schema from a staging project to a production project.
+//
+// All resource functions of this package are available as methods on Client. The
+// package-level functions are shortcuts that use the default client set up by Utils().
+//
+// Every operation also has a variant taking a context.Context, named with a Context suffix,
+// e.g. CreateDatabaseContext. Cancelling the context or reaching its deadline aborts the
+// request in flight and any wait for attributes or indexes, and the operation returns the
+// context's error.
+//
+// Example:
+//
+//	staging, err := appres.NewClient(
+//		appres.WithEndpoint("https://staging.example.com/v1"),
+//		appres.WithProject("staging-project"),
+//		appres.WithKey(os.Getenv("STAGING_KEY")),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	db, err := staging.CreateDatabase("my-database")
+type Client struct {
+	appwrite  *client.Client
+	databases *databases.Databases
+	storage   *storage.Storage
+
+	// ctx is the context requests and waits are bound to, set by withContext; nil means
+	// context.Background()
+	ctx context.Context
+
+	// logger receives the progress of every operation
+	logger *slog.Logger
+}
+
+// clientOptions collects the settings passed to NewClient.
+type clientOptions struct {
+	config helper.Config
+	retry  *RetryPolicy
+	logger *slog.Logger
+}
+
+// ClientOption configures a Client created with NewClient.
+type ClientOption func(*clientOptions)
+
+// WithEndpoint sets the Appwrite server endpoint URL, e.g. "https://cloud.appwrite.io/v1".
+func WithEndpoint(endpoint string) ClientOption {
+	return func(o *clientOptions) {
+		o.config.EndpointURL = endpoint
+	}
+}
+
+// WithProject sets the Appwrite project ID.
+func WithProject(project string) ClientOption {
+	return func(o *clientOptions) {
+		o.config.ProjectID = project
+	}
+}
+
+// WithKey sets the API key. It needs the database and storage scopes for the
+// operations that will be used.
+func WithKey(key string) ClientOption {
+	return func(o *clientOptions) {
+		o.config.APIKey = key
+	}
+}
+
+// WithConfig sets the endpoint, project and key from a helper.Config,
+// typically loaded with helper.LoadConfig or helper.LoadEnvironment.
+func WithConfig(cfg *helper.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.config = *cfg
+	}
+}
+
+// NewClient creates a Client for a single Appwrite project from explicit options.
+// Unlike Utils(), it does not read any environment variables or files.
+//
+// The configuration is checked before the client is returned: the endpoint, project and key
+// must all be set, the endpoint must be an http or https URL, and the server must answer
+// its health endpoint. No other request is made.
+//
+// Requests that fail with a transient error, such as rate limiting or a reset connection,
+// are retried according to DefaultRetryPolicy unless WithRetry sets another policy.
+//
+// Parameters:
+//   - opts: The options describing the project, usually WithEndpoint, WithProject and WithKey
+//
+// Returns:
+//   - *Client: The new client
+//   - error: *helper.MissingVariablesError if a setting is missing, *EndpointError if the
+//     endpoint URL is malformed, *UnreachableError if the server cannot be reached
+//
+// Example:
+//
+//	client, err := appres.NewClient(
+//		appres.WithEndpoint("https://cloud.appwrite.io/v1"),
+//		appres.WithProject("my-project"),
+//		appres.WithKey("my-api-key"),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// Or from the environment and a per-environment env file
+//	cfg, err := helper.LoadEnvironment("staging")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := appres.NewClient(appres.WithConfig(cfg))
+func NewClient(opts ...ClientOption) (*Client, error) {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.config.Validate(); err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimRight(o.config.EndpointURL, "/")
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, &EndpointError{URL: o.config.EndpointURL, Err: err}
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, &EndpointError{URL: o.config.EndpointURL, Err: errors.New("must be an absolute http or https URL")}
+	}
+	if err := ping(context.Background(), endpoint); err != nil {
+		return nil, err
+	}
+	clt := appwrite.NewClient(
+		appwrite.WithEndpoint(endpoint),
+		appwrite.WithProject(o.config.ProjectID),
+		appwrite.WithKey(o.config.APIKey),
+	)
+	retry := DefaultRetryPolicy
+	if o.retry != nil {
+		retry = *o.retry
+	}
+	logger := discardLogger
+	if o.logger != nil {
+		logger = o.logger
+	}
+	// The SDK's timeout would cover every attempt together, so it is applied per attempt instead
+	clt.Client.Transport = &retryTransport{policy: retry, timeout: clt.Client.Timeout, base: http.DefaultTransport, logger: logger}
+	clt.Client.Timeout = 0
+	return &Client{
+		appwrite:  &clt,
+		databases: appwrite.NewDatabases(clt),
+		storage:   appwrite.NewStorage(clt),
+		logger:    logger,
+	}, nil
+}
+
+// EndpointError is returned by NewClient when the endpoint URL is malformed
+// or does not serve the Appwrite API.
+type EndpointError struct {
+	// URL is the endpoint as configured
+	URL string
+
+	// Err describes what is wrong with it
+	Err error
+}
+
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("invalid Appwrite endpoint %q: %v", e.URL, e.Err)
+}
+
+func (e *EndpointError) Unwrap() error {
+	return e.Err
+}
+
+// UnreachableError is returned by NewClient when the Appwrite server does not respond.
+type UnreachableError struct {
+	// URL is the endpoint that was contacted
+	URL string
+
+	// Err is the underlying network or HTTP error
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("Appwrite server at %q is unreachable: %v", e.URL, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// pingTimeout bounds the connectivity check made by NewClient.
+const pingTimeout = 10 * time.Second
+
+// ping checks that the endpoint serves the Appwrite API by requesting its public
+// version endpoint, which needs no project or key.
+func ping(ctx context.Context, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/health/version", nil)
+	if err != nil {
+		return &EndpointError{URL: endpoint, Err: err}
+	}
+	httpClient := &http.Client{Timeout: pingTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return &UnreachableError{URL: endpoint, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return &EndpointError{URL: endpoint, Err: errors.New("no Appwrite API found, check the URL ends in /v1")}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &UnreachableError{URL: endpoint, Err: fmt.Errorf("health check returned %s", resp.Status)}
+	}
+	return nil
+}
+
+// defaultClient is the client used by the package-level functions.
+var defaultClient *Client
+
+// Default returns the client used by the package-level functions,
+// or nil if neither Utils() nor SetDefault() has been called.
+func Default() *Client {
+	return defaultClient
+}
+
+// SetDefault makes c the client used by the package-level functions.
+func SetDefault(c *Client) {
+	defaultClient = c
+	if c != nil {
+		AppwriteDatabase = c.databases
+		AppwriteStorage = c.storage
+	}
+}
+
+// std returns the default client or ErrNotInitialised.
+func std() (*Client, error) {
+	if defaultClient == nil {
+		return nil, ErrNotInitialised
+	}
+	return defaultClient, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// textHandler is a slog.Handler that prints records as short lines meant for people
+// rather than log processors, e.g.
+//
+//	Database created "blog" id=blog (84ms)
+//	warning: Retrying request method=GET path=/v1/databases attempt=1 delay=500ms error="503 Service Unavailable"
+//
+// The kind and action fields are left out, since the message already says both.
+type textHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+}
+
+// newTextHandler returns a textHandler writing records of at least the given level to w.
+func newTextHandler(w io.Writer, level slog.Leveler) *textHandler {
+	return &textHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+		b.WriteString("error: ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("warning: ")
+	}
+	b.WriteString(r.Message)
+
+	var duration string
+	write := func(a slog.Attr) bool {
+		switch a.Key {
+		case "kind", "action":
+		case "name":
+			fmt.Fprintf(&b, " %q", a.Value.String())
+		case "duration":
+			if d, ok := a.Value.Any().(time.Duration); ok {
+				duration = "<1ms"
+				if d >= time.Millisecond {
+					duration = d.Round(time.Millisecond).String()
+				}
+			}
+		default:
+			value := a.Value.String()
+			if strings.ContainsAny(value, " \"=") {
+				value = fmt.Sprintf("%q", value)
+			}
+			fmt.Fprintf(&b, " %s=%s", a.Key, value)
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(write)
+	if duration != "" {
+		fmt.Fprintf(&b, " (%s)", duration)
+	}
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup returns the handler unchanged: appres does not group its fields, so names
+// are printed without a group prefix.
+func (h *textHandler) WithGroup(string) slog.Handler {
+	return h
+}
@@ -0,0 +1,461 @@
+// Command appres manages the Appwrite resources of a project with appres schema documents.
+//
+// Usage:
+//
+//	appres validate [-format text|json] [schema.yaml]
+//	appres plan     [connection flags] [-recreate-indexes] [-format text|json|yaml] [schema.yaml]
+//	appres apply    [connection flags] [-recreate-indexes] [-format text|json|yaml] [schema.yaml]
+//	appres export   [connection flags] [-o schema.yaml] [-format yaml|json]
+//
+// The schema document defaults to schema.yaml in the current directory.
+//
+// The validate command checks the schema without contacting Appwrite. The plan command
+// prints what apply would change. The apply command plans first, refuses to change anything
+// if the plan has conflicts, and otherwise creates and updates the resources of the schema.
+// Appwrite cannot update indexes, so an index that differs from the schema is a conflict
+// unless -recreate-indexes is given, which deletes and creates it again.
+// The export command reads the databases, collections, attributes, indexes and buckets of
+// the project and writes them as a schema document, to standard output or to the file given
+// with -o. When writing to a file, the format is taken from its extension.
+//
+// The project is configured like appres.Utils(): APPWRITE_ENDPOINT_URL, APPWRITE_PROJECT_ID
+// and APPWRITE_API_KEY_APPRES are read from the environment, falling back to .env.local.
+// The connection flags take precedence over both:
+//
+//	-env file        read unset variables from this file instead of .env.local
+//	-endpoint url    Appwrite endpoint URL
+//	-project id      Appwrite project ID
+//	-key key         Appwrite API key
+//	-timeout d       give up after this duration, e.g. 10m; by default there is no limit
+//	-v               also report resources that are left unchanged
+//
+// Progress, such as every resource created or updated and every retried request, is
+// reported on standard error, so standard output only carries the plan or schema.
+// Interrupting the command, e.g. with Ctrl-C, cancels the requests in flight.
+// Exit codes:
+//
+//	0  success; for plan, the project matches the schema
+//	1  the command failed, e.g. an invalid schema or an Appwrite error
+//	2  invalid command line
+//	3  plan found changes or conflicts, or apply refused to run because of conflicts
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Haepapa/appres"
+	"github.com/Haepapa/appres/helper"
+	"gopkg.in/yaml.v3"
+)
+
+const usage = `Usage: appres <command> [flags] [schema.yaml]
+
+Commands:
+  validate  Check a schema document without contacting Appwrite
+  plan      Show what apply would change
+  apply     Create and update the resources of a schema document
+  export    Write the live project as a schema document
+
+Run "appres <command> -h" for the flags of a command.
+`
+
+// defaultSchema is the schema document used when none is given.
+const defaultSchema = "schema.yaml"
+
+// Exit codes, see the package documentation.
+const (
+	exitFailure = 1
+	exitUsage   = 2
+	exitDrift   = 3
+)
+
+// errDrift is returned by commands that have already reported the drift or conflicts
+// they found, so main only needs to set the exit code.
+var errDrift = errors.New("drift")
+
+// usageError is a problem with the command line. Errors found while parsing flags have no
+// message, since the flag package has already printed them together with the usage.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run runs the command given by args, the command line without the program name, and
+// returns the exit code.
+func run(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return exitUsage
+	}
+	var err error
+	switch args[0] {
+	case "validate":
+		err = runValidate(args[1:])
+	case "plan":
+		err = runPlan(args[1:])
+	case "apply":
+		err = runApply(args[1:])
+	case "export":
+		err = runExport(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "appres: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	var uerr *usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errDrift):
+		return exitDrift
+	case errors.As(err, &uerr):
+		if uerr.msg != "" {
+			fmt.Fprintln(os.Stderr, "appres:", err)
+		}
+		return exitUsage
+	default:
+		fmt.Fprintln(os.Stderr, "appres:", err)
+		return exitFailure
+	}
+}
+
+// runValidate implements the validate command.
+func runValidate(args []string) error {
+	fs := newFlagSet("validate")
+	format := fs.String("format", "text", "output format: text or json")
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format, "text", "json"); err != nil {
+		return err
+	}
+
+	schema, err := appres.LoadSchema(path)
+	if err != nil {
+		return err
+	}
+	verr := schema.Validate()
+	if *format == "json" {
+		report := struct {
+			Valid  bool     `json:"valid"`
+			Errors []string `json:"errors"`
+		}{Valid: verr == nil, Errors: []string{}}
+		for _, e := range unjoin(verr) {
+			report.Errors = append(report.Errors, e.Error())
+		}
+		if err := writeJSON(os.Stdout, report); err != nil {
+			return err
+		}
+	} else if verr == nil {
+		fmt.Printf("%s is valid\n", path)
+	} else {
+		fmt.Println(verr)
+	}
+	if verr != nil {
+		return fmt.Errorf("%s is not valid", path)
+	}
+	return nil
+}
+
+// runPlan implements the plan command.
+func runPlan(args []string) error {
+	fs := newFlagSet("plan")
+	conn := connectionFlags(fs)
+	format := fs.String("format", "text", "output format: text, json or yaml")
+	recreate := fs.Bool("recreate-indexes", false, "delete and create again indexes that differ from the schema")
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format, "text", "json", "yaml"); err != nil {
+		return err
+	}
+
+	ctx, cancel := conn.context()
+	defer cancel()
+	client, schema, err := load(conn, path)
+	if err != nil {
+		return err
+	}
+	opts := appres.ApplyOptions{RecreateIndexes: *recreate}
+	changes, err := client.PlanWithOptionsContext(ctx, schema, opts)
+	if err != nil {
+		return err
+	}
+	if err := writeChanges(os.Stdout, changes, *format); err != nil {
+		return err
+	}
+	if changes.Count(appres.ActionSkip) != len(changes.Changes) {
+		return errDrift
+	}
+	return nil
+}
+
+// runApply implements the apply command. It plans first, so a schema that conflicts with the
+// project is rejected before anything is changed.
+func runApply(args []string) error {
+	fs := newFlagSet("apply")
+	conn := connectionFlags(fs)
+	format := fs.String("format", "text", "output format of the plan: text, json or yaml")
+	recreate := fs.Bool("recreate-indexes", false, "delete and create again indexes that differ from the schema")
+	path, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format, "text", "json", "yaml"); err != nil {
+		return err
+	}
+
+	ctx, cancel := conn.context()
+	defer cancel()
+	client, schema, err := load(conn, path)
+	if err != nil {
+		return err
+	}
+	opts := appres.ApplyOptions{RecreateIndexes: *recreate}
+	changes, err := client.PlanWithOptionsContext(ctx, schema, opts)
+	if err != nil {
+		return err
+	}
+	if err := writeChanges(os.Stdout, changes, *format); err != nil {
+		return err
+	}
+	if changes.HasConflicts() {
+		fmt.Fprintln(os.Stderr, "appres: the schema conflicts with the project, nothing was changed")
+		return errDrift
+	}
+	if changes.Count(appres.ActionSkip) == len(changes.Changes) {
+		return nil
+	}
+	return client.ApplyWithOptionsContext(ctx, schema, opts)
+}
+
+// runExport implements the export command.
+func runExport(args []string) error {
+	fs := newFlagSet("export")
+	conn := connectionFlags(fs)
+	output := fs.String("o", "", "write the schema to this file instead of standard output")
+	format := fs.String("format", "yaml", "schema format when writing to standard output: yaml or json")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{}
+	}
+	if fs.NArg() > 0 {
+		return &usageError{msg: fmt.Sprintf("export takes no arguments, got %q", fs.Args())}
+	}
+	if err := checkFormat(*format, "yaml", "json"); err != nil {
+		return err
+	}
+
+	ctx, cancel := conn.context()
+	defer cancel()
+	client, err := conn.client()
+	if err != nil {
+		return err
+	}
+	schema, err := client.ExportContext(ctx)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		return appres.SaveSchema(*output, schema)
+	}
+	data, err := schema.Marshal(*format)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// connection holds the flags selecting the Appwrite project.
+type connection struct {
+	envFile  string
+	endpoint string
+	project  string
+	key      string
+	timeout  time.Duration
+	verbose  bool
+}
+
+// connectionFlags registers the connection flags on fs.
+func connectionFlags(fs *flag.FlagSet) *connection {
+	c := &connection{}
+	fs.StringVar(&c.envFile, "env", "", "read unset variables from this env file instead of .env.local")
+	fs.StringVar(&c.endpoint, "endpoint", "", "Appwrite endpoint URL (overrides "+helper.EnvEndpointURL+")")
+	fs.StringVar(&c.project, "project", "", "Appwrite project ID (overrides "+helper.EnvProjectID+")")
+	fs.StringVar(&c.key, "key", "", "Appwrite API key (overrides "+helper.EnvAPIKey+")")
+	fs.DurationVar(&c.timeout, "timeout", 0, "give up after this duration, e.g. 10m (default no limit)")
+	fs.BoolVar(&c.verbose, "v", false, "also report resources that are left unchanged")
+	return c
+}
+
+// context returns the context for the requests of a command. It is cancelled when the
+// process is interrupted or the -timeout passes.
+func (c *connection) context() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if c.timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// config reads the configuration from the environment and the env file, and overrides it
+// with the connection flags that are set. Flags take precedence over the environment,
+// which takes precedence over the env file.
+func (c *connection) config() (*helper.Config, error) {
+	var files []string
+	if c.envFile != "" {
+		files = append(files, c.envFile)
+	}
+	cfg, err := helper.ReadConfig(files...)
+	if err != nil {
+		return nil, err
+	}
+	if c.endpoint != "" {
+		cfg.EndpointURL = c.endpoint
+	}
+	if c.project != "" {
+		cfg.ProjectID = c.project
+	}
+	if c.key != "" {
+		cfg.APIKey = c.key
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// client creates a client from the configuration returned by config.
+func (c *connection) client() (*appres.Client, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+	level := slog.LevelInfo
+	if c.verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(newTextHandler(os.Stderr, level))
+	return appres.NewClient(appres.WithConfig(cfg), appres.WithLogger(logger))
+}
+
+// load reads the schema document and creates the client. The schema is read first, so a
+// missing or malformed document is reported without contacting Appwrite.
+func load(conn *connection, path string) (*appres.Client, *appres.Schema, error) {
+	schema, err := appres.LoadSchema(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := conn.client()
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, schema, nil
+}
+
+// newFlagSet creates the flag set of a command. Parse errors are returned rather than
+// exiting, so main decides the exit code.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: appres %s [flags]", name)
+		if name != "export" {
+			fmt.Fprint(fs.Output(), " [schema.yaml]")
+		}
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags of a command taking a schema document and returns its path.
+func parse(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", err
+		}
+		return "", &usageError{}
+	}
+	switch fs.NArg() {
+	case 0:
+		return defaultSchema, nil
+	case 1:
+		return fs.Arg(0), nil
+	default:
+		return "", &usageError{msg: fmt.Sprintf("%s takes one schema document, got %d", fs.Name(), fs.NArg())}
+	}
+}
+
+// checkFormat reports a usage error if format is not one of allowed.
+func checkFormat(format string, allowed ...string) error {
+	for _, a := range allowed {
+		if format == a {
+			return nil
+		}
+	}
+	return &usageError{msg: fmt.Sprintf("unsupported format %q", format)}
+}
+
+// writeChanges prints a change set in the given format.
+func writeChanges(w io.Writer, changes *appres.ChangeSet, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(w, changes)
+	case "yaml":
+		data, err := yaml.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		_, err := fmt.Fprint(w, changes)
+		return err
+	}
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// unjoin returns the errors joined in err.
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err == nil {
+		return nil
+	}
+	return []error{err}
+}
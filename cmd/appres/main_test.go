@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Haepapa/appres/helper"
+)
+
+// writeFile writes content to name in a temporary directory and returns its path.
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// quiet discards what the commands print for the rest of the test.
+func quiet(t *testing.T) {
+	t.Helper()
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = null, null
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		null.Close()
+	})
+}
+
+// clearEnv unsets the configuration variables for the rest of the test.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{helper.EnvEndpointURL, helper.EnvProjectID, helper.EnvAPIKey} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	file := writeFile(t, ".env.test", fmt.Sprintf("%s=https://file.example.com/v1\n%s=file-project\n%s=file-key\n",
+		helper.EnvEndpointURL, helper.EnvProjectID, helper.EnvAPIKey))
+	tests := []struct {
+		name string
+		env  map[string]string
+		conn connection
+		want helper.Config
+	}{
+		{
+			name: "file",
+			want: helper.Config{EndpointURL: "https://file.example.com/v1", ProjectID: "file-project", APIKey: "file-key"},
+		},
+		{
+			name: "environment over file",
+			env:  map[string]string{helper.EnvProjectID: "env-project", helper.EnvAPIKey: "env-key"},
+			want: helper.Config{EndpointURL: "https://file.example.com/v1", ProjectID: "env-project", APIKey: "env-key"},
+		},
+		{
+			name: "flags over environment",
+			env:  map[string]string{helper.EnvProjectID: "env-project", helper.EnvAPIKey: "env-key"},
+			conn: connection{endpoint: "https://flag.example.com/v1", key: "flag-key"},
+			want: helper.Config{EndpointURL: "https://flag.example.com/v1", ProjectID: "env-project", APIKey: "flag-key"},
+		},
+		{
+			name: "flags over file",
+			conn: connection{endpoint: "https://flag.example.com/v1", project: "flag-project", key: "flag-key"},
+			want: helper.Config{EndpointURL: "https://flag.example.com/v1", ProjectID: "flag-project", APIKey: "flag-key"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clearEnv(t)
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+			tc.conn.envFile = file
+			cfg, err := tc.conn.config()
+			if err != nil {
+				t.Fatalf("config: %v", err)
+			}
+			if *cfg != tc.want {
+				t.Errorf("got %+v, want %+v", *cfg, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfigFlagsCompleteMissingValues(t *testing.T) {
+	clearEnv(t)
+	file := writeFile(t, ".env.test", helper.EnvEndpointURL+"=https://file.example.com/v1\n")
+
+	conn := connection{envFile: file}
+	_, err := conn.config()
+	var missing *helper.MissingVariablesError
+	if !errors.As(err, &missing) || len(missing.Names) != 2 {
+		t.Errorf("got error %v, want the project ID and API key missing", err)
+	}
+
+	conn = connection{envFile: file, project: "flag-project", key: "flag-key"}
+	if _, err := conn.config(); err != nil {
+		t.Errorf("config with flags: %v", err)
+	}
+	if v := os.Getenv(helper.EnvProjectID); v != "" {
+		t.Errorf("%s set to %q, want the environment left untouched", helper.EnvProjectID, v)
+	}
+}
+
+// newProjectServer starts an Appwrite stand-in for a project with a single, empty database
+// with ID "main" named "main". Every request that would change the project fails.
+func newProjectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/health/version":
+			fmt.Fprint(w, `{"version":"1.5.0"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/databases":
+			fmt.Fprint(w, `{"total":1,"databases":[{"$id":"main","name":"main","enabled":true}]}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/databases/main/collections":
+			fmt.Fprint(w, `{"total":0,"collections":[]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found","code":404}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestExitCodes(t *testing.T) {
+	srv := newProjectServer(t)
+	clearEnv(t)
+	// The endpoint flag must win over the unreachable endpoint in the environment
+	t.Setenv(helper.EnvEndpointURL, "http://127.0.0.1:1/v1")
+	t.Setenv(helper.EnvProjectID, "test")
+	t.Setenv(helper.EnvAPIKey, "test")
+	env := writeFile(t, ".env.test", "")
+	// connected returns the arguments of a command connecting to srv
+	connected := func(command string, schema string) []string {
+		return []string{command, "-env", env, "-endpoint", srv.URL + "/v1", schema}
+	}
+
+	matching := writeFile(t, "matching.yaml", "databases:\n  - name: main\n    id: main\n")
+	changed := writeFile(t, "changed.yaml", "databases:\n  - name: main\n    id: main\n  - name: other\n    id: other\n")
+	conflicting := writeFile(t, "conflicting.yaml", "databases:\n  - name: main\n    id: primary\n")
+	invalid := writeFile(t, "invalid.yaml", "databases:\n  - name: main\n    collections:\n      - name: users\n        attributes:\n          - type: string\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"help", []string{"help"}, 0},
+		{"valid schema", []string{"validate", matching}, 0},
+		{"plan without changes", connected("plan", matching), 0},
+		{"apply without changes", connected("apply", matching), 0},
+
+		{"invalid schema", []string{"validate", invalid}, exitFailure},
+		{"missing schema", []string{"validate", filepath.Join(t.TempDir(), "missing.yaml")}, exitFailure},
+		{"apply failing", connected("apply", changed), exitFailure},
+
+		{"no command", nil, exitUsage},
+		{"unknown command", []string{"deploy"}, exitUsage},
+		{"unknown flag", []string{"plan", "-bogus"}, exitUsage},
+		{"two schemas", []string{"validate", matching, changed}, exitUsage},
+		{"unsupported format", []string{"plan", "-format", "xml", matching}, exitUsage},
+		{"export argument", []string{"export", matching}, exitUsage},
+
+		{"plan with changes", connected("plan", changed), exitDrift},
+		{"plan with conflicts", connected("plan", conflicting), exitDrift},
+		{"apply with conflicts", connected("apply", conflicting), exitDrift},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			quiet(t)
+			if got := run(tc.args); got != tc.want {
+				t.Errorf("run(%q) = %d, want %d", tc.args, got, tc.want)
+			}
+		})
+	}
+}
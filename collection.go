@@ -1,54 +1,402 @@
 package appres
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"time"
 
-	"github.com/appwrite/sdk-for-go/id"
+	"github.com/appwrite/sdk-for-go/databases"
 	"github.com/appwrite/sdk-for-go/models"
+
+	"github.com/Haepapa/appres/permissions"
 )
 
 // CreateCollection creates a new collection in the specified database or returns the existing one if it already exists.
 // It first checks if a collection with the given name already exists in the database to avoid duplicates.
 //
-// The function automatically generates a unique ID for new collections and logs the creation process.
+// The function automatically generates a unique ID for new collections and reports its progress to the logger set with WithLogger.
+// Use CreateCollectionWithID to choose the ID instead.
 //
 // Parameters:
 //   - dbId: The ID of the database where the collection should be created
 //   - name: The name of the collection to create
 //
-// Global Variables Used:
-//   - AppwriteDatabase: The initialized Appwrite database client
-//
 // Returns:
 //   - *models.Collection: Pointer to the created or existing collection
 //   - error: Any error that occurred during the operation
 //
 // Example:
 //
-//	col, err := app.CreateCollection(db.Id, "users")
+//	col, err := client.CreateCollection(db.Id, "users")
 //	if err != nil {
 //		log.Fatal("Failed to create collection:", err)
 //	}
 //	fmt.Printf("Collection created with ID: %s\n", col.Id)
+func (c *Client) CreateCollection(dbId string, name string) (*models.Collection, error) {
+	return c.CreateCollectionWithID(dbId, "", name)
+}
+
+// CreateCollection creates a collection using the default client initialised by Utils().
+// See Client.CreateCollection for details.
 func CreateCollection(dbId string, name string) (*models.Collection, error) {
-	// List all collections in database
-	collections, err := AppwriteDatabase.ListCollections(dbId)
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateCollection(dbId, name)
+}
+
+// CreateCollectionContext is like CreateCollection but sends its requests with ctx.
+func (c *Client) CreateCollectionContext(ctx context.Context, dbId string, name string) (*models.Collection, error) {
+	return c.withContext(ctx).CreateCollection(dbId, name)
+}
+
+// CreateCollectionContext is like CreateCollection but honours ctx, using the default client initialised by Utils().
+// See Client.CreateCollectionContext for details.
+func CreateCollectionContext(ctx context.Context, dbId string, name string) (*models.Collection, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateCollectionContext(ctx, dbId, name)
+}
+
+// CreateCollectionWithID creates a new collection with a caller-supplied ID or returns the existing
+// one if it already exists. Using the same ID in every environment lets application code refer
+// to the collection by a fixed ID such as "users".
+//
+// An existing collection is looked up by ID first and then by name. When colID is set and
+// the only collection with the name has a different ID, an *IDMismatchError is returned
+// instead of that collection.
+//
+// Parameters:
+//   - dbId: The ID of the database where the collection should be created
+//   - colID: The ID for the new collection, or an empty string to generate a unique ID
+//   - name: The name of the collection to create
+//
+// Returns:
+//   - *models.Collection: Pointer to the created or existing collection
+//   - error: Any error that occurred during the operation, or *IDMismatchError if the
+//     collection with the name has another ID
+//
+// Example:
+//
+//	col, err := client.CreateCollectionWithID(db.Id, "users", "Users")
+//	if err != nil {
+//		log.Fatal("Failed to create collection:", err)
+//	}
+func (c *Client) CreateCollectionWithID(dbId string, colID string, name string) (*models.Collection, error) {
+	existing, err := c.existingCollection(dbId, colID, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	// Create a collection
+	start := time.Now()
+	col, err := c.databases.CreateCollection(dbId, idOrUnique(colID), name)
+	if isConflict(err) {
+		if err := c.recoverConflict("collection", name, err, func() (found bool, err error) {
+			existing, err = c.existingCollection(dbId, colID, name)
+			return existing != nil, err
+		}, "id", colID); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error creating collection",
+			"kind", "collection", "name", name, "id", colID, "action", "create", "error", err)
+		return nil, err
+	}
+	c.logger.InfoContext(c.context(), "Collection created",
+		"kind", "collection", "name", col.Name, "id", col.Id, "action", "create", "duration", time.Since(start))
+	return col, nil
+}
+
+// CreateCollectionWithID creates a collection with a fixed ID using the default client initialised by Utils().
+// See Client.CreateCollectionWithID for details.
+func CreateCollectionWithID(dbId string, colID string, name string) (*models.Collection, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateCollectionWithID(dbId, colID, name)
+}
+
+// CreateCollectionWithIDContext is like CreateCollectionWithID but sends its requests with ctx.
+func (c *Client) CreateCollectionWithIDContext(ctx context.Context, dbId string, colID string, name string) (*models.Collection, error) {
+	return c.withContext(ctx).CreateCollectionWithID(dbId, colID, name)
+}
+
+// CreateCollectionWithIDContext is like CreateCollectionWithID but honours ctx, using the default client initialised by Utils().
+// See Client.CreateCollectionWithIDContext for details.
+func CreateCollectionWithIDContext(ctx context.Context, dbId string, colID string, name string) (*models.Collection, error) {
+	c, err := std()
 	if err != nil {
-		log.Println("Error listing collections:", err)
 		return nil, err
 	}
-	for _, col := range collections.Collections {
+	return c.CreateCollectionWithIDContext(ctx, dbId, colID, name)
+}
+
+// matchCollection returns the collection with the given ID, or failing that the given name.
+func matchCollection(collections []models.Collection, colID string, name string) *models.Collection {
+	if colID != "" {
+		for _, col := range collections {
+			if col.Id == colID {
+				return &col
+			}
+		}
+	}
+	for _, col := range collections {
 		if col.Name == name {
-			log.Println("Collection already exists with id:", col.Id)
-			return &col, nil
+			return &col
 		}
 	}
-	// Create a collection
-	col, err := AppwriteDatabase.CreateCollection(dbId, id.Unique(), name)
+	return nil
+}
+
+// existingCollection returns the collection with the given ID, or failing that the given
+// name, or nil if there is none.
+func (c *Client) existingCollection(dbId string, colID string, name string) (*models.Collection, error) {
+	col, err := c.findCollection(dbId, colID, name)
+	if col != nil {
+		c.logger.DebugContext(c.context(), "Collection already exists",
+			"kind", "collection", "name", col.Name, "id", col.Id, "action", "skip")
+	}
+	return col, err
+}
+
+// findCollection returns the collection with the given ID, or failing that the given name,
+// or nil if there is none. A collection matched by name with an ID other than colID is
+// reported as an *IDMismatchError.
+func (c *Client) findCollection(dbId string, colID string, name string) (*models.Collection, error) {
+	collections, err := c.listCollections(dbId)
 	if err != nil {
-		log.Println("Error creating collection:", err)
 		return nil, err
 	}
-	log.Println("Collection created with id:", col.Id)
+	col := matchCollection(collections, colID, name)
+	if col == nil {
+		return nil, nil
+	}
+	if err := checkID("collection", name, colID, col.Id); err != nil {
+		c.logger.ErrorContext(c.context(), "Collection exists with another ID",
+			"kind", "collection", "name", name, "id", col.Id, "action", "skip", "error", err)
+		return nil, err
+	}
 	return col, nil
-}
\ No newline at end of file
+}
+
+// EnsureCollection makes sure a collection exists with the settings of the CollectionType.
+// A missing collection is created with its ID, permissions, document security and enabled
+// flag. An existing collection, found by ID and then by name in the same way as
+// CreateCollectionWithID, is compared with DiffCollection and updated if it differs.
+//
+// Attributes and indexes of the CollectionType are not touched; use EnsureAttribute and
+// EnsureIndex, or Apply, for those.
+//
+// Parameters:
+//   - dbId: The ID of the database containing the collection
+//   - col: CollectionType struct containing the collection configuration
+//
+// Returns:
+//   - *models.Collection: Pointer to the created, updated or existing collection
+//   - error: Any error that occurred during the operation
+//
+// Example:
+//
+//	col, err := client.EnsureCollection(db.Id, appres.CollectionType{
+//		Name:             "posts",
+//		ID:               "posts",
+//		Permissions:      []string{"read(\"any\")", "create(\"users\")"},
+//		DocumentSecurity: appres.Ptr(true),
+//	})
+//	if err != nil {
+//		log.Fatal("Failed to ensure collection:", err)
+//	}
+func (c *Client) EnsureCollection(dbId string, col CollectionType) (*models.Collection, error) {
+	existing, err := c.findCollection(dbId, col.ID, col.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return c.updateCollection(dbId, existing, col)
+	}
+	var opts []databases.CreateCollectionOption
+	if col.DocumentSecurity != nil {
+		opts = append(opts, c.databases.WithCreateCollectionDocumentSecurity(*col.DocumentSecurity))
+	}
+	if col.Permissions != nil {
+		opts = append(opts, c.databases.WithCreateCollectionPermissions(col.Permissions))
+	}
+	if col.Enabled != nil {
+		opts = append(opts, c.databases.WithCreateCollectionEnabled(*col.Enabled))
+	}
+	start := time.Now()
+	created, err := c.databases.CreateCollection(dbId, idOrUnique(col.ID), col.Name, opts...)
+	if isConflict(err) {
+		var updated *models.Collection
+		if err := c.recoverConflict("collection", col.Name, err, func() (bool, error) {
+			existing, err := c.findCollection(dbId, col.ID, col.Name)
+			if existing == nil || err != nil {
+				return false, err
+			}
+			updated, err = c.updateCollection(dbId, existing, col)
+			return true, err
+		}, "id", col.ID); err != nil {
+			return nil, err
+		}
+		return updated, nil
+	}
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error creating collection",
+			"kind", "collection", "name", col.Name, "id", col.ID, "action", "create", "error", err)
+		return nil, err
+	}
+	c.logger.InfoContext(c.context(), "Collection created",
+		"kind", "collection", "name", created.Name, "id", created.Id, "action", "create", "duration", time.Since(start))
+	return created, nil
+}
+
+// EnsureCollection creates or updates a collection using the default client initialised by Utils().
+// See Client.EnsureCollection for details.
+func EnsureCollection(dbId string, col CollectionType) (*models.Collection, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.EnsureCollection(dbId, col)
+}
+
+// EnsureCollectionContext is like EnsureCollection but sends its requests with ctx.
+func (c *Client) EnsureCollectionContext(ctx context.Context, dbId string, col CollectionType) (*models.Collection, error) {
+	return c.withContext(ctx).EnsureCollection(dbId, col)
+}
+
+// EnsureCollectionContext is like EnsureCollection but honours ctx, using the default client initialised by Utils().
+// See Client.EnsureCollectionContext for details.
+func EnsureCollectionContext(ctx context.Context, dbId string, col CollectionType) (*models.Collection, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.EnsureCollectionContext(ctx, dbId, col)
+}
+
+// UpdateCollection brings an existing collection in line with the CollectionType.
+// The collection is found by ID and then by name, as by CreateCollectionWithID; when it is
+// found by ID, its name is updated as well. Permissions, DocumentSecurity and Enabled keep their current value when
+// left unset.
+//
+// Parameters:
+//   - dbId: The ID of the database containing the collection
+//   - col: CollectionType struct containing the desired collection configuration
+//
+// Returns:
+//   - *models.Collection: Pointer to the updated collection
+//   - error: Any error that occurred during the operation
+func (c *Client) UpdateCollection(dbId string, col CollectionType) (*models.Collection, error) {
+	existing, err := c.findCollection(dbId, col.ID, col.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("collection %q not found", col.Name)
+	}
+	return c.updateCollection(dbId, existing, col)
+}
+
+// UpdateCollection updates a collection using the default client initialised by Utils().
+// See Client.UpdateCollection for details.
+func UpdateCollection(dbId string, col CollectionType) (*models.Collection, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateCollection(dbId, col)
+}
+
+// UpdateCollectionContext is like UpdateCollection but sends its requests with ctx.
+func (c *Client) UpdateCollectionContext(ctx context.Context, dbId string, col CollectionType) (*models.Collection, error) {
+	return c.withContext(ctx).UpdateCollection(dbId, col)
+}
+
+// UpdateCollectionContext is like UpdateCollection but honours ctx, using the default client initialised by Utils().
+// See Client.UpdateCollectionContext for details.
+func UpdateCollectionContext(ctx context.Context, dbId string, col CollectionType) (*models.Collection, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateCollectionContext(ctx, dbId, col)
+}
+
+// updateCollection updates the existing collection if it differs from col.
+// Every setting is sent explicitly, since Appwrite resets omitted settings to their defaults.
+func (c *Client) updateCollection(dbId string, existing *models.Collection, col CollectionType) (*models.Collection, error) {
+	if len(DiffCollection(*existing, col)) == 0 {
+		c.logger.DebugContext(c.context(), "Collection already up to date",
+			"kind", "collection", "name", existing.Name, "id", existing.Id, "action", "skip")
+		return existing, nil
+	}
+	perms := existing.Permissions
+	if col.Permissions != nil {
+		perms = col.Permissions
+	}
+	documentSecurity := existing.DocumentSecurity
+	if col.DocumentSecurity != nil {
+		documentSecurity = *col.DocumentSecurity
+	}
+	enabled := existing.Enabled
+	if col.Enabled != nil {
+		enabled = *col.Enabled
+	}
+	start := time.Now()
+	updated, err := c.databases.UpdateCollection(
+		dbId,
+		existing.Id,
+		col.Name,
+		c.databases.WithUpdateCollectionPermissions(perms),
+		c.databases.WithUpdateCollectionDocumentSecurity(documentSecurity),
+		c.databases.WithUpdateCollectionEnabled(enabled),
+	)
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error updating collection",
+			"kind", "collection", "name", col.Name, "id", existing.Id, "action", "update", "error", err)
+		return nil, err
+	}
+	c.logger.InfoContext(c.context(), "Collection updated",
+		"kind", "collection", "name", updated.Name, "id", updated.Id, "action", "update", "duration", time.Since(start))
+	return updated, nil
+}
+
+// DiffCollection compares an existing collection with the requested CollectionType and returns
+// every setting that differs. An empty result means the collection matches.
+//
+// Permissions are compared with permissions.Equal and only when non-nil, and DocumentSecurity
+// and Enabled only when set.
+// Attributes and indexes are not compared; see DiffAttribute for those.
+//
+// Parameters:
+//   - live: The collection as returned by Appwrite
+//   - col: The requested collection configuration
+//
+// Returns:
+//   - []FieldDrift: Every mismatched setting, or nil if the collection matches
+func DiffCollection(live models.Collection, col CollectionType) []FieldDrift {
+	var drift []FieldDrift
+	if live.Name != col.Name {
+		drift = append(drift, FieldDrift{Field: "Name", Have: live.Name, Want: col.Name})
+	}
+	if col.Permissions != nil && !permissions.Equal(live.Permissions, col.Permissions) {
+		drift = append(drift, FieldDrift{Field: "Permissions", Have: live.Permissions, Want: col.Permissions})
+	}
+	if col.DocumentSecurity != nil && live.DocumentSecurity != *col.DocumentSecurity {
+		drift = append(drift, FieldDrift{Field: "DocumentSecurity", Have: live.DocumentSecurity, Want: *col.DocumentSecurity})
+	}
+	if col.Enabled != nil && live.Enabled != *col.Enabled {
+		drift = append(drift, FieldDrift{Field: "Enabled", Have: live.Enabled, Want: *col.Enabled})
+	}
+	return drift
+}
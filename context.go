@@ -0,0 +1,61 @@
+package appres
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/appwrite/sdk-for-go/appwrite"
+)
+
+// contextTransport attaches a context to every request sent through it, so requests made by
+// the Appwrite SDK, which has no context parameters, are cancelled with the context.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// withContext returns a copy of the client whose requests and waits are bound to ctx.
+// The copy shares the configuration of c but has its own HTTP client, so c itself is not
+// affected and can be used concurrently with other contexts.
+func (c *Client) withContext(ctx context.Context) *Client {
+	clt := *c.appwrite
+	httpClient := *clt.Client
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient.Transport = &contextTransport{ctx: ctx, base: base}
+	clt.Client = &httpClient
+	return &Client{
+		appwrite:  &clt,
+		databases: appwrite.NewDatabases(clt),
+		storage:   appwrite.NewStorage(clt),
+		ctx:       ctx,
+		logger:    c.logger,
+	}
+}
+
+// context returns the context the client is bound to, or context.Background() if none.
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// sleep pauses for d, returning early with the context's error if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
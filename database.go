@@ -1,7 +1,8 @@
 package appres
 
 import (
-	"log"
+	"context"
+	"time"
 
 	"github.com/appwrite/sdk-for-go/id"
 	"github.com/appwrite/sdk-for-go/models"
@@ -10,44 +11,174 @@ import (
 // CreateDatabase creates a new database with the specified name or returns the existing one if it already exists.
 // It first checks if a database with the given name already exists to avoid duplicates.
 //
-// The function automatically generates a unique ID for new databases and logs the creation process.
+// The function automatically generates a unique ID for new databases and reports its progress to the logger set with WithLogger.
+// Use CreateDatabaseWithID to choose the ID instead.
 //
 // Parameters:
 //   - name: The name of the database to create
 //
-// Global Variables Used:
-//   - AppwriteDatabase: The initialized Appwrite database client
-//
 // Returns:
 //   - *models.Database: Pointer to the created or existing database
 //   - error: Any error that occurred during the operation
 //
 // Example:
 //
-//	db, err := app.CreateDatabase("my-app-database")
+//	db, err := client.CreateDatabase("my-app-database")
 //	if err != nil {
 //		log.Fatal("Failed to create database:", err)
 //	}
 //	fmt.Printf("Database created with ID: %s\n", db.Id)
+func (c *Client) CreateDatabase(name string) (*models.Database, error) {
+	return c.CreateDatabaseWithID("", name)
+}
+
+// CreateDatabase creates a database using the default client initialised by Utils().
+// See Client.CreateDatabase for details.
 func CreateDatabase(name string) (*models.Database, error) {
-	// List all databases
-	databases, err := AppwriteDatabase.List()
+	c, err := std()
 	if err != nil {
-		log.Println("Error listing databases:", err)
 		return nil, err
 	}
-	for _, db := range databases.Databases {
-		if db.Name == name {
-			log.Println("Database already exists with id:", db.Id)
-			return &db, nil
-		}
+	return c.CreateDatabase(name)
+}
+
+// CreateDatabaseContext is like CreateDatabase but sends its requests with ctx, so a hung
+// endpoint is abandoned when ctx is cancelled or its deadline passes.
+func (c *Client) CreateDatabaseContext(ctx context.Context, name string) (*models.Database, error) {
+	return c.withContext(ctx).CreateDatabase(name)
+}
+
+// CreateDatabaseContext is like CreateDatabase but honours ctx, using the default client initialised by Utils().
+// See Client.CreateDatabaseContext for details.
+func CreateDatabaseContext(ctx context.Context, name string) (*models.Database, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateDatabaseContext(ctx, name)
+}
+
+// CreateDatabaseWithID creates a new database with a caller-supplied ID or returns the existing one
+// if it already exists. Using the same ID in every environment lets application code refer to
+// the database by a fixed ID such as "main".
+//
+// An existing database is looked up by ID first and then by name. When dbID is set and the
+// only database with the name has a different ID, an *IDMismatchError is returned instead of
+// that database.
+//
+// Parameters:
+//   - dbID: The ID for the new database, or an empty string to generate a unique ID
+//   - name: The name of the database to create
+//
+// Returns:
+//   - *models.Database: Pointer to the created or existing database
+//   - error: Any error that occurred during the operation, or *IDMismatchError if the
+//     database with the name has another ID
+//
+// Example:
+//
+//	db, err := client.CreateDatabaseWithID("main", "my-app-database")
+//	if err != nil {
+//		log.Fatal("Failed to create database:", err)
+//	}
+func (c *Client) CreateDatabaseWithID(dbID string, name string) (*models.Database, error) {
+	existing, err := c.existingDatabase(dbID, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
 	}
 	// Create a database
-	db, err := AppwriteDatabase.Create(id.Unique(), name)
+	start := time.Now()
+	db, err := c.databases.Create(idOrUnique(dbID), name)
+	if isConflict(err) {
+		if err := c.recoverConflict("database", name, err, func() (found bool, err error) {
+			existing, err = c.existingDatabase(dbID, name)
+			return existing != nil, err
+		}, "id", dbID); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error creating database",
+			"kind", "database", "name", name, "id", dbID, "action", "create", "error", err)
+		return nil, err
+	}
+	c.logger.InfoContext(c.context(), "Database created",
+		"kind", "database", "name", db.Name, "id", db.Id, "action", "create", "duration", time.Since(start))
+	return db, nil
+}
+
+// CreateDatabaseWithID creates a database with a fixed ID using the default client initialised by Utils().
+// See Client.CreateDatabaseWithID for details.
+func CreateDatabaseWithID(dbID string, name string) (*models.Database, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateDatabaseWithID(dbID, name)
+}
+
+// CreateDatabaseWithIDContext is like CreateDatabaseWithID but sends its requests with ctx.
+func (c *Client) CreateDatabaseWithIDContext(ctx context.Context, dbID string, name string) (*models.Database, error) {
+	return c.withContext(ctx).CreateDatabaseWithID(dbID, name)
+}
+
+// CreateDatabaseWithIDContext is like CreateDatabaseWithID but honours ctx, using the default client initialised by Utils().
+// See Client.CreateDatabaseWithIDContext for details.
+func CreateDatabaseWithIDContext(ctx context.Context, dbID string, name string) (*models.Database, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateDatabaseWithIDContext(ctx, dbID, name)
+}
+
+// existingDatabase returns the database with the given ID, or failing that the given name,
+// or nil if there is none. A database matched by name with an ID other than dbID is
+// reported as an *IDMismatchError.
+func (c *Client) existingDatabase(dbID string, name string) (*models.Database, error) {
+	databases, err := c.listDatabases()
 	if err != nil {
-		log.Println("Error creating database:", err)
 		return nil, err
 	}
-	log.Println("Database created with id:", db.Id)
+	db := matchDatabase(databases, dbID, name)
+	if db == nil {
+		return nil, nil
+	}
+	if err := checkID("database", name, dbID, db.Id); err != nil {
+		c.logger.ErrorContext(c.context(), "Database exists with another ID",
+			"kind", "database", "name", name, "id", db.Id, "action", "skip", "error", err)
+		return nil, err
+	}
+	c.logger.DebugContext(c.context(), "Database already exists",
+		"kind", "database", "name", db.Name, "id", db.Id, "action", "skip")
 	return db, nil
-}
\ No newline at end of file
+}
+
+// matchDatabase returns the database with the given ID, or failing that the given name.
+func matchDatabase(databases []models.Database, dbID string, name string) *models.Database {
+	if dbID != "" {
+		for _, db := range databases {
+			if db.Id == dbID {
+				return &db
+			}
+		}
+	}
+	for _, db := range databases {
+		if db.Name == name {
+			return &db
+		}
+	}
+	return nil
+}
+
+// idOrUnique returns the caller-supplied ID, or a generated unique ID if it is empty.
+func idOrUnique(resourceID string) string {
+	if resourceID == "" {
+		return id.Unique()
+	}
+	return resourceID
+}
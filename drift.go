@@ -0,0 +1,314 @@
+package appres
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FieldDrift describes a single field where an existing resource differs from its definition.
+type FieldDrift struct {
+	// Field is the name of the AttributeType field that differs, e.g. "Size" or "Default"
+	Field string
+
+	// Have is the value currently stored in Appwrite
+	Have interface{}
+
+	// Want is the value requested by the definition
+	Want interface{}
+}
+
+// String renders the drift as "Field: have X, want Y".
+func (d FieldDrift) String() string {
+	return fmt.Sprintf("%s: have %v, want %v", d.Field, d.Have, d.Want)
+}
+
+// AttributeDriftError is returned by CreateAttribute when an attribute with the requested
+// key already exists but its configuration differs from the AttributeType.
+// Fields lists every mismatch, so a single error describes the full drift.
+//
+// Example:
+//
+//	err := appres.CreateAttribute(db.Id, col.Id, attr)
+//	var drift *appres.AttributeDriftError
+//	if errors.As(err, &drift) {
+//		for _, f := range drift.Fields {
+//			log.Println("drift on", drift.Key, f)
+//		}
+//	}
+type AttributeDriftError struct {
+	// Key is the key of the drifted attribute
+	Key string
+
+	// Fields lists every field that differs
+	Fields []FieldDrift
+}
+
+func (e *AttributeDriftError) Error() string {
+	return fmt.Sprintf("attribute %q differs from definition: %s", e.Key, formatDrift(e.Fields))
+}
+
+// IDMismatchError is returned when a database, collection or bucket is requested with an
+// explicit ID, but the only existing one with the requested name has a different ID.
+// The existing resource is neither returned nor updated, since application code would
+// otherwise refer to an ID that does not exist. Use ExistingID, or rename one of the two,
+// to resolve it.
+type IDMismatchError struct {
+	// Kind is the resource kind: "database", "collection" or "bucket"
+	Kind string
+
+	// Name is the name shared by the requested and the existing resource
+	Name string
+
+	// ID is the requested ID
+	ID string
+
+	// ExistingID is the ID of the existing resource
+	ExistingID string
+}
+
+func (e *IDMismatchError) Error() string {
+	return fmt.Sprintf("%s %q exists with ID %q instead of %q", e.Kind, e.Name, e.ExistingID, e.ID)
+}
+
+// checkID returns an *IDMismatchError if id is set and differs from the ID of the existing
+// resource, which must then have been matched by name.
+func checkID(kind string, name string, id string, existingID string) error {
+	if id == "" || id == existingID {
+		return nil
+	}
+	return &IDMismatchError{Kind: kind, Name: name, ID: id, ExistingID: existingID}
+}
+
+// formatDrift joins drifted fields into a single line.
+func formatDrift(fields []FieldDrift) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DiffAttribute compares an attribute returned by ListAttributes with the requested AttributeType
+// and returns every field that differs. An empty result means the attribute matches.
+//
+// Optional settings that were left unset in the AttributeType (nil Min, Max or Default, empty
+// TwoWayKey or OnDelete) are not compared, since Appwrite fills them with its own defaults.
+//
+// Parameters:
+//   - live: The attribute map as returned in models.AttributeList.Attributes
+//   - att: The requested attribute configuration
+//
+// Returns:
+//   - []FieldDrift: Every mismatched field, or nil if the attribute matches
+func DiffAttribute(live map[string]interface{}, att AttributeType) []FieldDrift {
+	have := attributeFromMap(live)
+	var drift []FieldDrift
+	add := func(field string, h, w interface{}) {
+		drift = append(drift, FieldDrift{Field: field, Have: h, Want: w})
+	}
+	if have.Type != att.Type {
+		// Nothing else is comparable across types
+		add("Type", have.Type, att.Type)
+		return drift
+	}
+	if att.Type == "relationship" {
+		if have.RelatedCollectionID != att.RelatedCollectionID {
+			add("RelatedCollectionID", have.RelatedCollectionID, att.RelatedCollectionID)
+		}
+		if have.RelationshipType != att.RelationshipType {
+			add("RelationshipType", have.RelationshipType, att.RelationshipType)
+		}
+		if have.TwoWay != att.TwoWay {
+			add("TwoWay", have.TwoWay, att.TwoWay)
+		}
+		if att.TwoWayKey != "" && have.TwoWayKey != att.TwoWayKey {
+			add("TwoWayKey", have.TwoWayKey, att.TwoWayKey)
+		}
+		if att.OnDelete != "" && have.OnDelete != att.OnDelete {
+			add("OnDelete", have.OnDelete, att.OnDelete)
+		}
+		return drift
+	}
+	if att.Type == "string" && have.Size != att.Size {
+		add("Size", have.Size, att.Size)
+	}
+	if att.Type == "enum" && !sameSet(have.Elements, att.Elements) {
+		add("Elements", have.Elements, att.Elements)
+	}
+	if have.Required != att.Required {
+		add("Required", have.Required, att.Required)
+	}
+	if have.Array != att.Array {
+		add("Array", have.Array, att.Array)
+	}
+	if _, ok := live["encrypt"]; ok && have.Encrypt != att.Encrypt {
+		add("Encrypt", have.Encrypt, att.Encrypt)
+	}
+	// Appwrite never stores a default on a required attribute
+	if att.Default != nil && !att.Required && !sameValue(att.Type, have.Default, att.Default) {
+		add("Default", have.Default, att.Default)
+	}
+	if att.Min != nil && !sameValue(att.Type, have.Min, att.Min) {
+		add("Min", have.Min, att.Min)
+	}
+	if att.Max != nil && !sameValue(att.Type, have.Max, att.Max) {
+		add("Max", have.Max, att.Max)
+	}
+	return drift
+}
+
+// immutableFields lists the AttributeType fields Appwrite cannot change on an existing attribute.
+var immutableFields = map[string]bool{
+	"Type":                true,
+	"Array":               true,
+	"Encrypt":             true,
+	"RelatedCollectionID": true,
+	"RelationshipType":    true,
+	"TwoWay":              true,
+	"TwoWayKey":           true,
+}
+
+// immutableDrift returns the drifted fields that UpdateAttribute cannot change.
+func immutableDrift(drift []FieldDrift) []FieldDrift {
+	var fixed []FieldDrift
+	for _, f := range drift {
+		if immutableFields[f.Field] {
+			fixed = append(fixed, f)
+		}
+	}
+	return fixed
+}
+
+// attributeFromMap converts an untyped attribute returned by ListAttributes into an AttributeType.
+// Appwrite reports email, url, enum and ip attributes as strings with a format, and float
+// attributes as doubles, which are mapped back to the AttributeType.Type value that creates them.
+func attributeFromMap(attr map[string]interface{}) AttributeType {
+	att := AttributeType{}
+	att.Type, _ = attr["type"].(string)
+	if format, ok := attr["format"].(string); ok && att.Type == "string" && format != "" {
+		att.Type = format
+	}
+	if att.Type == "double" {
+		att.Type = "float"
+	}
+	att.Name, _ = attr["key"].(string)
+	att.Required, _ = attr["required"].(bool)
+	att.Array, _ = attr["array"].(bool)
+	att.Encrypt, _ = attr["encrypt"].(bool)
+	if size, ok := attr["size"].(float64); ok {
+		att.Size = int(size)
+	}
+	att.Default = attr["default"]
+	if att.Type == "integer" {
+		att.Default = intValue(att.Default)
+		att.Min = intValue(attr["min"])
+		att.Max = intValue(attr["max"])
+	}
+	if att.Type == "float" {
+		att.Min = floatValue(attr["min"])
+		att.Max = floatValue(attr["max"])
+	}
+	if elements, ok := attr["elements"].([]interface{}); ok {
+		for _, e := range elements {
+			if s, ok := e.(string); ok {
+				att.Elements = append(att.Elements, s)
+			}
+		}
+	}
+	if att.Type == "relationship" {
+		att.RelatedCollectionID, _ = attr["relatedCollection"].(string)
+		att.RelationshipType, _ = attr["relationType"].(string)
+		att.TwoWay, _ = attr["twoWay"].(bool)
+		att.TwoWayKey, _ = attr["twoWayKey"].(string)
+		att.OnDelete, _ = attr["onDelete"].(string)
+	}
+	return att
+}
+
+// intValue converts a decoded JSON number to an int.
+// Appwrite reports an unset integer bound as the 64-bit limit, which is returned as nil.
+func intValue(v interface{}) interface{} {
+	f, ok := v.(float64)
+	if !ok {
+		return v
+	}
+	if f <= math.MinInt64 || f >= math.MaxInt64 {
+		return nil
+	}
+	return int(f)
+}
+
+// floatValue returns a float bound, or nil for the largest float Appwrite reports when
+// the bound is unset.
+func floatValue(v interface{}) interface{} {
+	if f, ok := v.(float64); ok && math.Abs(f) >= math.MaxFloat64 {
+		return nil
+	}
+	return v
+}
+
+// sameValue compares a stored value with a requested one for the given attribute type.
+// Numbers are compared by value, datetimes by instant and empty strings as unset, so
+// representation differences such as 5 and 5.0 or "Z" and "+00:00" are not reported as drift.
+func sameValue(attType string, have, want interface{}) bool {
+	// An empty string default is stored as no default
+	if have == "" {
+		have = nil
+	}
+	if want == "" {
+		want = nil
+	}
+	if have == nil || want == nil {
+		return have == nil && want == nil
+	}
+	if hf, ok := toFloat(have); ok {
+		if wf, ok := toFloat(want); ok {
+			return hf == wf
+		}
+	}
+	if attType == "datetime" {
+		hs, hok := have.(string)
+		ws, wok := want.(string)
+		if hok && wok {
+			ht, herr := time.Parse(time.RFC3339, hs)
+			wt, werr := time.Parse(time.RFC3339, ws)
+			if herr == nil && werr == nil {
+				return ht.Equal(wt)
+			}
+		}
+	}
+	return reflect.DeepEqual(have, want)
+}
+
+// toFloat returns v as a float64 if it is a Go numeric type.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// sameSet reports whether a and b contain the same strings, ignoring order.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = slices.Clone(a)
+	b = slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
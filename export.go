@@ -0,0 +1,151 @@
+package appres
+
+import (
+	"context"
+
+	"github.com/appwrite/sdk-for-go/models"
+)
+
+// Export reads the databases, collections, attributes, indexes and buckets of the project and
+// returns them as a Schema. Saving the result with SaveSchema gives a document that Apply can
+// use to re-create the project elsewhere, which brings projects set up by hand in the Appwrite
+// console under version control.
+//
+// Every resource keeps its ID, so applying the exported schema to another project gives it the
+// same IDs. Attributes are converted back into AttributeType values; settings Appwrite fills in
+// by itself, such as the unset bounds of integer attributes, are left out. The reverse side of
+// a two-way relationship is not exported, since Appwrite creates it together with the
+// relationship.
+//
+// Returns:
+//   - *Schema: The schema describing the project
+//   - error: Any error that occurred while listing the resources
+//
+// Example:
+//
+//	schema, err := client.Export()
+//	if err != nil {
+//		log.Fatal("Failed to export project:", err)
+//	}
+//	if err := appres.SaveSchema("schema.yaml", schema); err != nil {
+//		log.Fatal("Failed to save schema:", err)
+//	}
+func (c *Client) Export() (*Schema, error) {
+	schema := &Schema{}
+	databases, err := c.listDatabases()
+	if err != nil {
+		return nil, err
+	}
+	for _, db := range databases {
+		dbDef := DatabaseType{Name: db.Name, ID: db.Id}
+		collections, err := c.listCollections(db.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range collections {
+			colDef, err := c.exportCollection(db.Id, col)
+			if err != nil {
+				return nil, err
+			}
+			dbDef.Collections = append(dbDef.Collections, colDef)
+		}
+		schema.Databases = append(schema.Databases, dbDef)
+	}
+	buckets, err := c.listBuckets()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range buckets {
+		fileSecurity, enabled, encryption, antivirus := b.FileSecurity, b.Enabled, b.Encryption, b.Antivirus
+		schema.Buckets = append(schema.Buckets, BucketType{
+			Name:                  b.Name,
+			ID:                    b.Id,
+			Permissions:           b.Permissions,
+			FileSecurity:          &fileSecurity,
+			Enabled:               &enabled,
+			MaxFileSize:           b.MaximumFileSize,
+			AllowedFileExtensions: b.AllowedFileExtensions,
+			Compression:           b.Compression,
+			Encryption:            &encryption,
+			Antivirus:             &antivirus,
+		})
+	}
+	return schema, nil
+}
+
+// Export exports the project of the default client initialised by Utils().
+// See Client.Export for details.
+func Export() (*Schema, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.Export()
+}
+
+// ExportContext is like Export but sends its requests with ctx.
+func (c *Client) ExportContext(ctx context.Context) (*Schema, error) {
+	return c.withContext(ctx).Export()
+}
+
+// ExportContext is like Export but honours ctx, using the default client initialised by Utils().
+// See Client.ExportContext for details.
+func ExportContext(ctx context.Context) (*Schema, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.ExportContext(ctx)
+}
+
+// exportCollection converts a collection with its attributes and indexes into a CollectionType.
+func (c *Client) exportCollection(dbID string, col models.Collection) (CollectionType, error) {
+	documentSecurity, enabled := col.DocumentSecurity, col.Enabled
+	colDef := CollectionType{
+		Name:             col.Name,
+		ID:               col.Id,
+		Permissions:      col.Permissions,
+		DocumentSecurity: &documentSecurity,
+		Enabled:          &enabled,
+	}
+	attributes, err := c.listAttributes(dbID, col.Id)
+	if err != nil {
+		return colDef, err
+	}
+	for _, attr := range attributes {
+		if side, _ := attr["side"].(string); side == "child" {
+			continue
+		}
+		colDef.Attributes = append(colDef.Attributes, exportAttribute(attr))
+	}
+	indexes, err := c.listIndexes(dbID, col.Id)
+	if err != nil {
+		return colDef, err
+	}
+	for _, idx := range indexes {
+		colDef.Indexes = append(colDef.Indexes, IndexType{
+			Key:        idx.Key,
+			Type:       idx.Type,
+			Attributes: idx.Attributes,
+			Orders:     idx.Orders,
+		})
+	}
+	return colDef, nil
+}
+
+// exportAttribute converts an attribute returned by ListAttributes into an AttributeType,
+// dropping the values Appwrite reports for settings that do not apply to the attribute.
+func exportAttribute(attr map[string]interface{}) AttributeType {
+	att := attributeFromMap(attr)
+	if att.Required || att.Default == "" {
+		att.Default = nil
+	}
+	if att.Type != "string" {
+		att.Size = 0
+		att.Encrypt = false
+	}
+	if att.Type == "relationship" && !att.TwoWay {
+		att.TwoWayKey = ""
+	}
+	return att
+}
@@ -7,4 +7,5 @@ toolchain go1.23.10
 require (
 	github.com/appwrite/sdk-for-go v0.7.0
 	github.com/joho/godotenv v1.5.1
+	gopkg.in/yaml.v3 v3.0.1
 )
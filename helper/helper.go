@@ -3,48 +3,192 @@
 package helper
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+// Names of the environment variables holding the Appwrite configuration.
+const (
+	// EnvEndpointURL holds the Appwrite server endpoint URL
+	EnvEndpointURL = "APPWRITE_ENDPOINT_URL"
+
+	// EnvProjectID holds the Appwrite project identifier
+	EnvProjectID = "APPWRITE_PROJECT_ID"
+
+	// EnvAPIKey holds the API key used for resource definition operations
+	EnvAPIKey = "APPWRITE_API_KEY_APPRES"
+)
+
+// DefaultEnvFile is the env file read by LoadConfig when no files are given.
+const DefaultEnvFile = ".env.local"
+
 // Global variables that store Appwrite configuration loaded from environment variables.
 // These are populated by calling Envvars() and should not be modified directly.
 var (
-    // AppwriteEndpointURL is the Appwrite server endpoint URL
-    AppwriteEndpointURL string
-    
-    // AppwriteProjectID is the Appwrite project identifier
-    AppwriteProjectID  string
-    
-    // AppwriteRESDEFAPIKey is the API key used for resource definition operations
-    AppwriteRESDEFAPIKey    string
+	// AppwriteEndpointURL is the Appwrite server endpoint URL
+	AppwriteEndpointURL string
+
+	// AppwriteProjectID is the Appwrite project identifier
+	AppwriteProjectID string
+
+	// AppwriteRESDEFAPIKey is the API key used for resource definition operations
+	AppwriteRESDEFAPIKey string
 )
 
-// Envvars loads environment variables from the .env.local file and populates the global
+// Config holds the settings needed to connect to an Appwrite project.
+type Config struct {
+	// EndpointURL is the Appwrite server endpoint URL
+	EndpointURL string
+
+	// ProjectID is the Appwrite project identifier
+	ProjectID string
+
+	// APIKey is the API key used for resource definition operations
+	APIKey string
+}
+
+// MissingVariablesError is returned when required configuration values are not set.
+type MissingVariablesError struct {
+	// Names lists the environment variables that are missing
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return "missing Appwrite configuration: " + strings.Join(e.Names, ", ")
+}
+
+// Validate checks that the endpoint, project ID and API key are all present.
+// It returns a *MissingVariablesError naming every missing variable.
+func (c *Config) Validate() error {
+	var missing []string
+	if c.EndpointURL == "" {
+		missing = append(missing, EnvEndpointURL)
+	}
+	if c.ProjectID == "" {
+		missing = append(missing, EnvProjectID)
+	}
+	if c.APIKey == "" {
+		missing = append(missing, EnvAPIKey)
+	}
+	if len(missing) > 0 {
+		return &MissingVariablesError{Names: missing}
+	}
+	return nil
+}
+
+// LoadConfig builds a Config from the process environment and, optionally, env files.
+//
+// Plain environment variables are read first and always take precedence, so variables
+// injected by CI or a container runtime work without any file. Values that are not set in
+// the environment are then taken from the given files, in order; the first file defining a
+// variable wins. Every file given explicitly must exist.
+//
+// When no files are given, DefaultEnvFile (.env.local) is read if it exists and ignored otherwise.
+//
+// The process environment is not modified. The returned Config is validated and a
+// *MissingVariablesError is returned if any value is still missing.
+//
+// Example:
+//
+//	// Environment only, falling back to .env.local when present
+//	cfg, err := helper.LoadConfig()
+//
+//	// Explicit files, e.g. shared defaults plus per-environment overrides
+//	cfg, err := helper.LoadConfig(".env.staging", ".env")
+func LoadConfig(files ...string) (*Config, error) {
+	cfg, err := ReadConfig(files...)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ReadConfig is like LoadConfig but does not validate the Config, so missing values can be
+// filled in from another source, such as command line flags, before calling Config.Validate.
+//
+// Example:
+//
+//	cfg, err := helper.ReadConfig()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if *endpoint != "" {
+//		cfg.EndpointURL = *endpoint
+//	}
+//	if err := cfg.Validate(); err != nil {
+//		log.Fatal(err)
+//	}
+func ReadConfig(files ...string) (*Config, error) {
+	cfg := &Config{
+		EndpointURL: os.Getenv(EnvEndpointURL),
+		ProjectID:   os.Getenv(EnvProjectID),
+		APIKey:      os.Getenv(EnvAPIKey),
+	}
+	optional := false
+	if len(files) == 0 {
+		files = []string{DefaultEnvFile}
+		optional = true
+	}
+	for _, file := range files {
+		vars, err := godotenv.Read(file)
+		if err != nil {
+			if optional && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("loading %s: %w", file, err)
+		}
+		setIfEmpty(&cfg.EndpointURL, vars[EnvEndpointURL])
+		setIfEmpty(&cfg.ProjectID, vars[EnvProjectID])
+		setIfEmpty(&cfg.APIKey, vars[EnvAPIKey])
+	}
+	return cfg, nil
+}
+
+// LoadEnvironment loads the configuration for a named environment from the file
+// ".env.<name>", e.g. LoadEnvironment("staging") reads .env.staging.
+// Plain environment variables still take precedence, as with LoadConfig.
+func LoadEnvironment(name string) (*Config, error) {
+	return LoadConfig(".env." + name)
+}
+
+// setIfEmpty assigns value to dst unless dst is already set.
+func setIfEmpty(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+// Envvars loads the Appwrite configuration with LoadConfig and populates the global
 // configuration variables required for Appwrite client initialisation.
 //
 // This function reads the following environment variables:
 //   - APPWRITE_ENDPOINT_URL: The Appwrite server endpoint URL
-//   - APPWRITE_PROJECT_ID: The Appwrite project ID  
+//   - APPWRITE_PROJECT_ID: The Appwrite project ID
 //   - APPWRITE_API_KEY_APPRES: The API key with appropriate permissions
 //
-// The function will terminate the program with log.Fatalf if the .env.local file
-// cannot be loaded. Ensure the file exists in the current working directory
-// and contains all required environment variables.
+// Variables set in the process environment are used directly; the .env.local file is only
+// needed for values that are not. A *MissingVariablesError is returned if any variable is
+// missing, and the global variables are left unchanged.
 //
 // This function is typically called automatically by appres.Utils() and should
 // not need to be called directly by users of the appres package.
-func Envvars() {
-    // Load .env.local file
-    err := godotenv.Load(".env.local")
-    if err != nil {
-        log.Fatalf("Error loading .env.local file")
-    }
-
-    // Reference variables
-    AppwriteEndpointURL = os.Getenv("APPWRITE_ENDPOINT_URL")
-    AppwriteProjectID = os.Getenv("APPWRITE_PROJECT_ID")
-    AppwriteRESDEFAPIKey = os.Getenv("APPWRITE_API_KEY_APPRES")
+func Envvars() error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Reference variables
+	AppwriteEndpointURL = cfg.EndpointURL
+	AppwriteProjectID = cfg.ProjectID
+	AppwriteRESDEFAPIKey = cfg.APIKey
+	return nil
 }
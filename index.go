@@ -0,0 +1,310 @@
+package appres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appwrite/sdk-for-go/databases"
+)
+
+// liveIndex is an index as returned by ListIndexes.
+// The SDK's models.Index does not decode the index type, so the list is decoded into this instead.
+type liveIndex struct {
+	Key        string   `json:"key"`
+	Type       string   `json:"type"`
+	Status     string   `json:"status"`
+	Attributes []string `json:"attributes"`
+	Orders     []string `json:"orders"`
+}
+
+// IndexDriftError is returned by CreateIndex, EnsureIndex and Apply when an index with the
+// requested key already exists but its configuration differs from the IndexType.
+type IndexDriftError struct {
+	// Key is the key of the drifted index
+	Key string
+
+	// Fields lists every field that differs
+	Fields []FieldDrift
+}
+
+func (e *IndexDriftError) Error() string {
+	return fmt.Sprintf("index %q differs from definition: %s", e.Key, formatDrift(e.Fields))
+}
+
+// CreateIndex creates a new index in the specified collection or skips creation if it already exists.
+// It checks for duplicates by key in the same way as CreateAttribute: an existing index that
+// matches idx is left untouched, while one that differs is reported as an *IndexDriftError.
+//
+// The attributes referenced by the index must already be available. Use EnsureIndex to wait
+// for freshly created attributes first.
+//
+// Parameters:
+//   - dbID: The ID of the database containing the collection
+//   - colID: The ID of the collection where the index should be created
+//   - idx: IndexType struct containing the index configuration
+//
+// Returns:
+//   - error: Any error that occurred during the operation, *IndexDriftError if an existing
+//     index differs, or nil if successful
+//
+// Example:
+//
+//	idx := app.IndexType{
+//		Key:        "username_unique",
+//		Type:       "unique",
+//		Attributes: []string{"username"},
+//	}
+//	err := client.CreateIndex(db.Id, col.Id, idx)
+//	if err != nil {
+//		log.Fatal("Failed to create index:", err)
+//	}
+func (c *Client) CreateIndex(dbID string, colID string, idx IndexType) error {
+	if found, err := c.existingIndex(dbID, colID, idx); found || err != nil {
+		return err
+	}
+	var opts []databases.CreateIndexOption
+	if len(idx.Orders) > 0 {
+		opts = append(opts, c.databases.WithCreateIndexOrders(idx.Orders))
+	}
+	start := time.Now()
+	_, err := c.databases.CreateIndex(
+		dbID,
+		colID,
+		idx.Key,
+		idx.Type,
+		idx.Attributes,
+		opts...,
+	)
+	if isConflict(err) {
+		return c.recoverConflict("index", idx.Key, err, func() (bool, error) {
+			return c.existingIndex(dbID, colID, idx)
+		}, "collection", colID)
+	}
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error creating index",
+			"kind", "index", "name", idx.Key, "collection", colID, "action", "create", "error", err)
+		return err
+	}
+	c.logger.InfoContext(c.context(), "Index created",
+		"kind", "index", "name", idx.Key, "collection", colID, "action", "create", "duration", time.Since(start))
+	return nil
+}
+
+// CreateIndex creates an index using the default client initialised by Utils().
+// See Client.CreateIndex for details.
+func CreateIndex(dbID string, colID string, idx IndexType) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.CreateIndex(dbID, colID, idx)
+}
+
+// CreateIndexContext is like CreateIndex but sends its requests with ctx.
+func (c *Client) CreateIndexContext(ctx context.Context, dbID string, colID string, idx IndexType) error {
+	return c.withContext(ctx).CreateIndex(dbID, colID, idx)
+}
+
+// CreateIndexContext is like CreateIndex but honours ctx, using the default client initialised by Utils().
+// See Client.CreateIndexContext for details.
+func CreateIndexContext(ctx context.Context, dbID string, colID string, idx IndexType) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.CreateIndexContext(ctx, dbID, colID, idx)
+}
+
+// EnsureIndex makes sure the index exists and matches the IndexType.
+// It first waits for every attribute referenced by the index to become available, since
+// Appwrite creates attributes asynchronously and rejects indexes on attributes still being
+// processed. System attributes such as $createdAt are always available and are not waited
+// for. Appwrite cannot update indexes, so an existing index that differs is left untouched
+// and reported as an *IndexDriftError; use RecreateIndex to replace it.
+//
+// Parameters:
+//   - dbID: The ID of the database containing the collection
+//   - colID: The ID of the collection where the index should exist
+//   - idx: IndexType struct containing the index configuration
+//
+// Returns:
+//   - error: Any error that occurred during the operation, *IndexDriftError if an existing
+//     index differs, or nil if successful
+func (c *Client) EnsureIndex(dbID string, colID string, idx IndexType) error {
+	var keys []string
+	for _, key := range idx.Attributes {
+		if !strings.HasPrefix(key, "$") {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		if err := c.WaitForAttributes(dbID, colID, keys, DefaultWaitTimeout); err != nil {
+			return err
+		}
+	}
+	return c.CreateIndex(dbID, colID, idx)
+}
+
+// EnsureIndex creates an index using the default client initialised by Utils().
+// See Client.EnsureIndex for details.
+func EnsureIndex(dbID string, colID string, idx IndexType) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.EnsureIndex(dbID, colID, idx)
+}
+
+// EnsureIndexContext is like EnsureIndex but sends its requests with ctx, and stops waiting for
+// the index attributes when ctx is done.
+func (c *Client) EnsureIndexContext(ctx context.Context, dbID string, colID string, idx IndexType) error {
+	return c.withContext(ctx).EnsureIndex(dbID, colID, idx)
+}
+
+// EnsureIndexContext is like EnsureIndex but honours ctx, using the default client initialised by Utils().
+// See Client.EnsureIndexContext for details.
+func EnsureIndexContext(ctx context.Context, dbID string, colID string, idx IndexType) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.EnsureIndexContext(ctx, dbID, colID, idx)
+}
+
+// RecreateIndex is like EnsureIndex, but deletes an existing index that differs from the
+// IndexType and creates it again. The index is unavailable until Appwrite has rebuilt it, so
+// queries relying on it may fail or slow down in the meantime, and a unique index is created
+// again only if the existing documents satisfy it.
+//
+// Parameters:
+//   - dbID: The ID of the database containing the collection
+//   - colID: The ID of the collection where the index should exist
+//   - idx: IndexType struct containing the index configuration
+//
+// Returns:
+//   - error: Any error that occurred during the operation, or nil if successful
+//
+// Example:
+//
+//	err := client.EnsureIndex(db.Id, col.Id, idx)
+//	var drift *app.IndexDriftError
+//	if errors.As(err, &drift) {
+//		err = client.RecreateIndex(db.Id, col.Id, idx)
+//	}
+//	if err != nil {
+//		log.Fatal("Failed to create index:", err)
+//	}
+func (c *Client) RecreateIndex(dbID string, colID string, idx IndexType) error {
+	err := c.EnsureIndex(dbID, colID, idx)
+	var drift *IndexDriftError
+	if !errors.As(err, &drift) {
+		return err
+	}
+	start := time.Now()
+	if _, err := c.databases.DeleteIndex(dbID, colID, idx.Key); err != nil {
+		c.logger.ErrorContext(c.context(), "Error deleting index",
+			"kind", "index", "name", idx.Key, "collection", colID, "action", "delete", "error", err)
+		return err
+	}
+	c.logger.InfoContext(c.context(), "Index deleted for recreation",
+		"kind", "index", "name", idx.Key, "collection", colID, "action", "delete", "duration", time.Since(start))
+	if err := c.waitForIndexDeletion(dbID, colID, idx.Key, DefaultWaitTimeout); err != nil {
+		return err
+	}
+	return c.CreateIndex(dbID, colID, idx)
+}
+
+// RecreateIndex creates or recreates an index using the default client initialised by Utils().
+// See Client.RecreateIndex for details.
+func RecreateIndex(dbID string, colID string, idx IndexType) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.RecreateIndex(dbID, colID, idx)
+}
+
+// RecreateIndexContext is like RecreateIndex but sends its requests with ctx, and stops waiting
+// for the index attributes, or for a replaced index to be deleted, when ctx is done.
+func (c *Client) RecreateIndexContext(ctx context.Context, dbID string, colID string, idx IndexType) error {
+	return c.withContext(ctx).RecreateIndex(dbID, colID, idx)
+}
+
+// RecreateIndexContext is like RecreateIndex but honours ctx, using the default client initialised by Utils().
+// See Client.RecreateIndexContext for details.
+func RecreateIndexContext(ctx context.Context, dbID string, colID string, idx IndexType) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.RecreateIndexContext(ctx, dbID, colID, idx)
+}
+
+// existingIndex looks the index up in the collection. It reports whether the index exists,
+// with an *IndexDriftError if it differs from idx.
+func (c *Client) existingIndex(dbID string, colID string, idx IndexType) (bool, error) {
+	indexes, err := c.listIndexes(dbID, colID)
+	if err != nil {
+		return false, err
+	}
+	for _, live := range indexes {
+		if live.Key == idx.Key {
+			if drift := diffIndex(live, idx); len(drift) > 0 {
+				err := &IndexDriftError{Key: idx.Key, Fields: drift}
+				c.logger.DebugContext(c.context(), "Index already exists but differs",
+					"kind", "index", "name", idx.Key, "collection", colID, "action", "skip", "error", err)
+				return true, err
+			}
+			c.logger.DebugContext(c.context(), "Index already exists",
+				"kind", "index", "name", idx.Key, "collection", colID, "action", "skip")
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// diffIndex compares an existing index with the requested IndexType.
+// Orders are only compared when the IndexType sets them.
+func diffIndex(live liveIndex, idx IndexType) []FieldDrift {
+	var drift []FieldDrift
+	if live.Type != idx.Type {
+		drift = append(drift, FieldDrift{Field: "Type", Have: live.Type, Want: idx.Type})
+	}
+	if strings.Join(live.Attributes, ",") != strings.Join(idx.Attributes, ",") {
+		drift = append(drift, FieldDrift{Field: "Attributes", Have: live.Attributes, Want: idx.Attributes})
+	}
+	if len(idx.Orders) > 0 && !strings.EqualFold(strings.Join(live.Orders, ","), strings.Join(idx.Orders, ",")) {
+		drift = append(drift, FieldDrift{Field: "Orders", Have: live.Orders, Want: idx.Orders})
+	}
+	return drift
+}
+
+// waitForIndexDeletion polls the collection until the index with the given key is gone.
+func (c *Client) waitForIndexDeletion(dbID string, colID string, key string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		indexes, err := c.listIndexes(dbID, colID)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, live := range indexes {
+			if live.Key == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for index %q to be deleted", key)
+		}
+		if err := sleep(c.context(), pollInterval); err != nil {
+			return err
+		}
+	}
+}
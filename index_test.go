@@ -0,0 +1,124 @@
+package appres
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// indexServer is an Appwrite stand-in serving the index endpoints of a single collection.
+// It answers list requests with its indexes, and creates and deletes them immediately.
+type indexServer struct {
+	mu      sync.Mutex
+	indexes []liveIndex
+	created []string
+	deleted []string
+}
+
+func (s *indexServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v1/health/version" {
+		fmt.Fprint(w, `{"version":"1.5.0"}`)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	const path = "/v1/databases/db/collections/col/indexes"
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == path:
+		json.NewEncoder(w).Encode(map[string]interface{}{"total": len(s.indexes), "indexes": s.indexes})
+	case r.Method == http.MethodPost && r.URL.Path == path:
+		var idx liveIndex
+		if err := json.NewDecoder(r.Body).Decode(&idx); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		idx.Status = "available"
+		s.indexes = append(s.indexes, idx)
+		s.created = append(s.created, idx.Key)
+		json.NewEncoder(w).Encode(idx)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, path+"/"):
+		key := strings.TrimPrefix(r.URL.Path, path+"/")
+		for i, idx := range s.indexes {
+			if idx.Key == key {
+				s.indexes = append(s.indexes[:i], s.indexes[i+1:]...)
+				break
+			}
+		}
+		s.deleted = append(s.deleted, key)
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, `{"message":"not found","code":404}`, http.StatusNotFound)
+	}
+}
+
+// newIndexTestClient starts an indexServer holding the given indexes and returns a client
+// connected to it.
+func newIndexTestClient(t *testing.T, indexes ...liveIndex) (*Client, *indexServer) {
+	t.Helper()
+	fake := &indexServer{indexes: indexes}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	c, err := NewClient(
+		WithEndpoint(srv.URL+"/v1"),
+		WithProject("test"),
+		WithKey("test"),
+		WithRetry(NoRetry),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c, fake
+}
+
+// createdAtIndex indexes a system attribute, so no attribute has to be waited for.
+var createdAtIndex = IndexType{Key: "by_created", Type: "key", Attributes: []string{"$createdAt"}}
+
+func TestEnsureIndexReportsDrift(t *testing.T) {
+	c, fake := newIndexTestClient(t, liveIndex{Key: "by_created", Type: "unique", Status: "available", Attributes: []string{"$createdAt"}})
+	err := c.EnsureIndex("db", "col", createdAtIndex)
+	var drift *IndexDriftError
+	if !errors.As(err, &drift) {
+		t.Fatalf("got error %v, want *IndexDriftError", err)
+	}
+	if len(fake.deleted) != 0 || len(fake.created) != 0 {
+		t.Errorf("got %v deleted and %v created, want the index left untouched", fake.deleted, fake.created)
+	}
+}
+
+func TestRecreateIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    []liveIndex
+		wantDeleted int
+		wantCreated int
+	}{
+		{"missing", nil, 0, 1},
+		{"matching", []liveIndex{{Key: "by_created", Type: "key", Status: "available", Attributes: []string{"$createdAt"}}}, 0, 0},
+		{"drifted", []liveIndex{{Key: "by_created", Type: "unique", Status: "available", Attributes: []string{"$createdAt"}}}, 1, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, fake := newIndexTestClient(t, tc.existing...)
+			if err := c.RecreateIndex("db", "col", createdAtIndex); err != nil {
+				t.Fatalf("RecreateIndex: %v", err)
+			}
+			if len(fake.deleted) != tc.wantDeleted {
+				t.Errorf("got %d indexes deleted, want %d", len(fake.deleted), tc.wantDeleted)
+			}
+			if len(fake.created) != tc.wantCreated {
+				t.Errorf("got %d indexes created, want %d", len(fake.created), tc.wantCreated)
+			}
+			if len(fake.indexes) != 1 || fake.indexes[0].Type != "key" {
+				t.Errorf("got indexes %+v, want only the key index", fake.indexes)
+			}
+		})
+	}
+}
@@ -1,43 +1,60 @@
 package appres
 
 import (
-	"github.com/appwrite/sdk-for-go/appwrite"
 	"github.com/appwrite/sdk-for-go/databases"
 	"github.com/appwrite/sdk-for-go/storage"
 
 	"github.com/Haepapa/appres/helper"
 )
 
-// AppwriteDatabase is the global database client instance used by all database operations.
-// It is initialised by calling Utils() and should not be accessed directly.
+// AppwriteDatabase and AppwriteStorage are the services of the default client.
+// They are initialised by calling Utils() and kept for compatibility; use Default()
+// or a Client created with NewClient instead of accessing them directly.
 var (
 	AppwriteDatabase *databases.Databases
-	AppwriteStorage   *storage.Storage
+	AppwriteStorage  *storage.Storage
 )
 
-// Utils initialises the Appwrite client with configuration from environment variables.
-// It loads environment variables from the .env.local file and creates a new Appwrite client
-// with the configured endpoint, project ID, and API key.
+// Utils initialises the default Appwrite client with configuration from environment variables.
+// It reads the variables from the process environment, falling back to the .env.local file
+// for any that are not set, and creates a new Appwrite client with the configured endpoint,
+// project ID, and API key.
 //
-// This function must be called before using any other functions in this package.
-// It will terminate the program if the .env.local file cannot be loaded.
+// This function must be called before using the package-level functions of this package.
+// It never terminates the program; configuration and connectivity problems are returned
+// as the typed errors described on NewClient. To work with several projects, or with
+// explicit configuration, use NewClient instead.
+//
+// Options such as WithLogger or WithRetry are passed on to NewClient; the endpoint, project
+// and key always come from the environment.
 //
 // Environment variables required:
 //   - APPWRITE_ENDPOINT_URL: The Appwrite server endpoint URL
-//   - APPWRITE_PROJECT_ID: The Appwrite project ID  
+//   - APPWRITE_PROJECT_ID: The Appwrite project ID
 //   - APPWRITE_API_KEY_APPRES: The API key with database and storage permissions
 //
 // Example:
 //
-//	app.Utils()
+//	if err := app.Utils(); err != nil {
+//		log.Fatal(err)
+//	}
 //	// Now you can use other functions such as CreateDatabase, CreateCollection, etc.
-func Utils() {
-	helper.Envvars()
-	client := appwrite.NewClient(
-		appwrite.WithEndpoint(helper.AppwriteEndpointURL),
-		appwrite.WithProject(helper.AppwriteProjectID),
-		appwrite.WithKey(helper.AppwriteRESDEFAPIKey),
+//
+//	// Or with progress logged to standard error
+//	err := app.Utils(app.WithLogger(slog.Default()))
+func Utils(opts ...ClientOption) error {
+	if err := helper.Envvars(); err != nil {
+		return err
+	}
+	opts = append(opts,
+		WithEndpoint(helper.AppwriteEndpointURL),
+		WithProject(helper.AppwriteProjectID),
+		WithKey(helper.AppwriteRESDEFAPIKey),
 	)
-	AppwriteDatabase = appwrite.NewDatabases(client)
-	AppwriteStorage = appwrite.NewStorage(client)
-}
\ No newline at end of file
+	client, err := NewClient(opts...)
+	if err != nil {
+		return err
+	}
+	SetDefault(client)
+	return nil
+}
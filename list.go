@@ -0,0 +1,116 @@
+package appres
+
+import (
+	"github.com/appwrite/sdk-for-go/models"
+	"github.com/appwrite/sdk-for-go/query"
+)
+
+// listPageSize is the number of resources requested per page. Appwrite returns only 25
+// resources when no limit is given, so every list is paged explicitly.
+const listPageSize = 100
+
+// listAll collects every page of a list request. list is called with the queries selecting
+// a page, starting with the first, and returns the resources of that page; key returns the
+// ID or key of a resource, which selects the next page with query.CursorAfter. Paging stops
+// at the first page with fewer than listPageSize resources.
+func listAll[T any](list func(queries []string) ([]T, error), key func(T) string) ([]T, error) {
+	var all []T
+	queries := []string{query.Limit(listPageSize)}
+	for {
+		page, err := list(queries)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < listPageSize {
+			return all, nil
+		}
+		queries = []string{query.Limit(listPageSize), query.CursorAfter(key(page[len(page)-1]))}
+	}
+}
+
+// listDatabases returns every database of the project.
+func (c *Client) listDatabases() ([]models.Database, error) {
+	databases, err := listAll(func(queries []string) ([]models.Database, error) {
+		list, err := c.databases.List(c.databases.WithListQueries(queries))
+		if err != nil {
+			return nil, err
+		}
+		return list.Databases, nil
+	}, func(db models.Database) string { return db.Id })
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error listing databases", "kind", "database", "action", "list", "error", err)
+	}
+	return databases, err
+}
+
+// listCollections returns every collection of a database.
+func (c *Client) listCollections(dbID string) ([]models.Collection, error) {
+	collections, err := listAll(func(queries []string) ([]models.Collection, error) {
+		list, err := c.databases.ListCollections(dbID, c.databases.WithListCollectionsQueries(queries))
+		if err != nil {
+			return nil, err
+		}
+		return list.Collections, nil
+	}, func(col models.Collection) string { return col.Id })
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error listing collections", "kind", "collection", "action", "list", "error", err)
+	}
+	return collections, err
+}
+
+// listAttributes returns every attribute of a collection, as decoded by the SDK.
+func (c *Client) listAttributes(dbID string, colID string) ([]map[string]interface{}, error) {
+	attributes, err := listAll(func(queries []string) ([]map[string]interface{}, error) {
+		list, err := c.databases.ListAttributes(dbID, colID, c.databases.WithListAttributesQueries(queries))
+		if err != nil {
+			return nil, err
+		}
+		return list.Attributes, nil
+	}, func(attr map[string]interface{}) string {
+		key, _ := attr["key"].(string)
+		return key
+	})
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error listing attributes",
+			"kind", "attribute", "collection", colID, "action", "list", "error", err)
+	}
+	return attributes, err
+}
+
+// listIndexes returns every index of a collection including its type.
+func (c *Client) listIndexes(dbID string, colID string) ([]liveIndex, error) {
+	indexes, err := listAll(func(queries []string) ([]liveIndex, error) {
+		list, err := c.databases.ListIndexes(dbID, colID, c.databases.WithListIndexesQueries(queries))
+		if err != nil {
+			return nil, err
+		}
+		var decoded struct {
+			Indexes []liveIndex `json:"indexes"`
+		}
+		if err := list.Decode(&decoded); err != nil {
+			return nil, err
+		}
+		return decoded.Indexes, nil
+	}, func(idx liveIndex) string { return idx.Key })
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error listing indexes",
+			"kind", "index", "collection", colID, "action", "list", "error", err)
+	}
+	return indexes, err
+}
+
+// listBuckets returns every storage bucket of the project.
+func (c *Client) listBuckets() ([]models.Bucket, error) {
+	buckets, err := listAll(func(queries []string) ([]models.Bucket, error) {
+		list, err := c.storage.ListBuckets(c.storage.WithListBucketsQueries(queries))
+		if err != nil {
+			return nil, err
+		}
+		return list.Buckets, nil
+	}, func(b models.Bucket) string { return b.Id })
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error listing buckets", "kind", "bucket", "action", "list", "error", err)
+	}
+	return buckets, err
+}
@@ -0,0 +1,66 @@
+package appres
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/appwrite/sdk-for-go/query"
+)
+
+func TestListAllPages(t *testing.T) {
+	for _, total := range []int{0, 1, listPageSize - 1, listPageSize, 2*listPageSize + 7} {
+		t.Run(fmt.Sprint(total), func(t *testing.T) {
+			var items []string
+			for i := 0; i < total; i++ {
+				items = append(items, fmt.Sprintf("item%04d", i))
+			}
+			var calls [][]string
+			got, err := listAll(func(queries []string) ([]string, error) {
+				calls = append(calls, queries)
+				start := 0
+				for _, q := range queries {
+					for i, item := range items {
+						if q == query.CursorAfter(item) {
+							start = i + 1
+						}
+					}
+				}
+				return items[start:min(start+listPageSize, len(items))], nil
+			}, func(item string) string { return item })
+			if err != nil {
+				t.Fatalf("listAll: %v", err)
+			}
+			if !slices.Equal(got, items) {
+				t.Errorf("got %d items, want %d", len(got), len(items))
+			}
+			if want := total/listPageSize + 1; len(calls) != want {
+				t.Errorf("got %d pages requested, want %d", len(calls), want)
+			}
+			for _, queries := range calls {
+				if !slices.Contains(queries, query.Limit(listPageSize)) {
+					t.Errorf("page requested without limit: %v", queries)
+				}
+			}
+		})
+	}
+}
+
+func TestListAllStopsAtError(t *testing.T) {
+	failure := errors.New("list failed")
+	pages := 0
+	_, err := listAll(func(queries []string) ([]int, error) {
+		pages++
+		if pages == 2 {
+			return nil, failure
+		}
+		return make([]int, listPageSize), nil
+	}, func(int) string { return "x" })
+	if !errors.Is(err, failure) {
+		t.Errorf("got error %v, want %v", err, failure)
+	}
+	if pages != 2 {
+		t.Errorf("got %d pages requested, want 2", pages)
+	}
+}
@@ -0,0 +1,44 @@
+package appres
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger sets the logger the client reports its progress to. Clients created without it
+// log nothing.
+//
+// Every record describes one resource with these fields, where they apply:
+//   - kind: "database", "collection", "attribute", "index" or "bucket"
+//   - name: the name of the resource, or the key of an attribute or index
+//   - id: the ID of the resource
+//   - collection: the ID of the collection holding an attribute or index
+//   - action: what was done, e.g. "create", "update", "skip", "delete", "list", "wait" or "retry"
+//   - duration: how long a create, update, delete or wait took
+//   - error: the error that made the action fail
+//
+// Created, updated and deleted resources are logged at Info level, resources that already
+// exist at Debug level, retried requests at Warn level and failures at Error level.
+//
+// Example:
+//
+//	client, err := appres.NewClient(
+//		appres.WithConfig(cfg),
+//		appres.WithLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil))),
+//	)
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// discardLogger is the logger of clients created without WithLogger.
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler that drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -2,11 +2,12 @@
 // It simplifies the process of creating various resources in your Appwrite backend.
 //
 // This package offers a simplified interface to the Appwrite Go SDK, providing functions to:
-//   - Initialize the Appwrite client
+//   - Initialize the Appwrite client, or several independent clients with NewClient
 //   - Create databases with duplicate checking
 //   - Create collections within databases
-//   - Create various types of attributes (string, email, integer, datetime, boolean, relationship, url)
+//   - Create various types of attributes (string, email, integer, float, datetime, boolean, relationship, url, enum, ip)
 //   - Create storage buckets with security and file constraints
+//   - Apply a declarative YAML or JSON schema describing all of the above
 //
 // All functions include built-in duplicate checking to prevent errors when resources already exist.
 //
@@ -21,7 +22,9 @@
 //
 //	func main() {
 //		// Initialize the Appwrite client (required first step)
-//		appres.Utils()
+//		if err := appres.Utils(); err != nil {
+//			log.Fatal(err)
+//		}
 //
 //		// Create a database
 //		db, err := appres.CreateDatabase("my-database")
@@ -50,7 +53,8 @@
 //
 // Environment Setup:
 //
-// Before using this package, create a .env.local file in your project root with:
+// Before using this package, set the following environment variables or put them in a
+// .env.local file in your project root:
 //
 //	APPWRITE_ENDPOINT_URL=https://your-appwrite-endpoint.com/v1
 //	APPWRITE_PROJECT_ID=your-project-id
@@ -0,0 +1,222 @@
+// Package permissions provides typed constructors for Appwrite permission strings, so
+// permissions for buckets, collections and documents can be built without hand-writing
+// strings such as `read("any")`, and parsing of existing permission strings for comparison.
+//
+// Example:
+//
+//	bucket := appres.BucketType{
+//		Name: "avatars",
+//		Permissions: permissions.Strings(
+//			permissions.Read(permissions.Any()),
+//			permissions.Write(permissions.User("5c1f88b4")),
+//			permissions.Update(permissions.Team("admins", "owner")),
+//		),
+//	}
+package permissions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Action is the operation a permission grants.
+type Action string
+
+// Actions supported by Appwrite. ActionWrite is shorthand that Appwrite expands into
+// ActionCreate, ActionUpdate and ActionDelete.
+const (
+	ActionRead   Action = "read"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionWrite  Action = "write"
+)
+
+// Role is the subject a permission is granted to, e.g. "any" or "user:5c1f88b4".
+type Role string
+
+// Any grants the permission to anyone, signed in or not.
+func Any() Role {
+	return "any"
+}
+
+// Guests grants the permission to visitors who are not signed in.
+func Guests() Role {
+	return "guests"
+}
+
+// Users grants the permission to every signed-in user. An optional status such as
+// "verified" or "unverified" limits it to users with that status.
+func Users(status ...string) Role {
+	return withSuffix("users", status)
+}
+
+// User grants the permission to a single user. An optional status such as "verified"
+// or "unverified" limits it to when the user has that status.
+func User(id string, status ...string) Role {
+	return withSuffix("user:"+id, status)
+}
+
+// Team grants the permission to every member of a team, or, when a team role such as
+// "owner" is given, only to members with that role.
+func Team(id string, role ...string) Role {
+	return withSuffix("team:"+id, role)
+}
+
+// Member grants the permission to a single team membership.
+func Member(id string) Role {
+	return Role("member:" + id)
+}
+
+// Label grants the permission to every user with the given label.
+func Label(name string) Role {
+	return Role("label:" + name)
+}
+
+// withSuffix appends the first optional suffix to a role, separated by a slash.
+func withSuffix(role string, suffix []string) Role {
+	if len(suffix) > 0 && suffix[0] != "" {
+		role += "/" + suffix[0]
+	}
+	return Role(role)
+}
+
+// Permission grants an action to a role.
+type Permission struct {
+	// Action is the granted operation
+	Action Action
+
+	// Role is the subject the operation is granted to
+	Role Role
+}
+
+// Read grants role permission to read a resource.
+func Read(role Role) Permission {
+	return Permission{Action: ActionRead, Role: role}
+}
+
+// Create grants role permission to create resources, e.g. documents in a collection.
+func Create(role Role) Permission {
+	return Permission{Action: ActionCreate, Role: role}
+}
+
+// Update grants role permission to update a resource.
+func Update(role Role) Permission {
+	return Permission{Action: ActionUpdate, Role: role}
+}
+
+// Delete grants role permission to delete a resource.
+func Delete(role Role) Permission {
+	return Permission{Action: ActionDelete, Role: role}
+}
+
+// Write grants role permission to create, update and delete.
+func Write(role Role) Permission {
+	return Permission{Action: ActionWrite, Role: role}
+}
+
+// String returns the permission in Appwrite's format, e.g. `read("any")`.
+func (p Permission) String() string {
+	return fmt.Sprintf("%s(%q)", p.Action, string(p.Role))
+}
+
+// Strings converts permissions to the string form used by BucketType.Permissions,
+// CollectionType.Permissions and the Appwrite SDK.
+func Strings(perms ...Permission) []string {
+	out := make([]string, len(perms))
+	for i, p := range perms {
+		out[i] = p.String()
+	}
+	return out
+}
+
+// Parse parses a permission string such as `read("any")` or `update("team:admins/owner")`.
+// It returns an error if the action is unknown or the string is malformed.
+func Parse(s string) (Permission, error) {
+	s = strings.TrimSpace(s)
+	open := strings.IndexByte(s, '(')
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return Permission{}, fmt.Errorf("invalid permission %q: expected action(\"role\")", s)
+	}
+	action := Action(s[:open])
+	switch action {
+	case ActionRead, ActionCreate, ActionUpdate, ActionDelete, ActionWrite:
+	default:
+		return Permission{}, fmt.Errorf("invalid permission %q: unknown action %q", s, action)
+	}
+	role := strings.TrimSpace(s[open+1 : len(s)-1])
+	if len(role) < 2 || role[0] != '"' || role[len(role)-1] != '"' {
+		return Permission{}, fmt.Errorf("invalid permission %q: role must be quoted", s)
+	}
+	role = role[1 : len(role)-1]
+	if role == "" {
+		return Permission{}, fmt.Errorf("invalid permission %q: empty role", s)
+	}
+	return Permission{Action: action, Role: Role(role)}, nil
+}
+
+// ParseAll parses every permission string, stopping at the first invalid one.
+func ParseAll(perms []string) ([]Permission, error) {
+	out := make([]Permission, 0, len(perms))
+	for _, s := range perms {
+		p, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Normalise expands write permissions into create, update and delete, as Appwrite does when
+// it stores them, and removes duplicates. The result is sorted so that two normalised lists
+// granting the same access are equal.
+func Normalise(perms []Permission) []Permission {
+	var out []Permission
+	for _, p := range perms {
+		if p.Action == ActionWrite {
+			out = append(out, Create(p.Role), Update(p.Role), Delete(p.Role))
+			continue
+		}
+		out = append(out, p)
+	}
+	slices.SortFunc(out, func(a, b Permission) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return slices.Compact(out)
+}
+
+// Diff compares two permission lists after normalising both, and returns the permissions
+// in want that are missing from have and the permissions in have that are not in want.
+func Diff(have, want []Permission) (missing, extra []Permission) {
+	have = Normalise(have)
+	want = Normalise(want)
+	for _, p := range want {
+		if !slices.Contains(have, p) {
+			missing = append(missing, p)
+		}
+	}
+	for _, p := range have {
+		if !slices.Contains(want, p) {
+			extra = append(extra, p)
+		}
+	}
+	return missing, extra
+}
+
+// Equal reports whether two lists of permission strings grant the same access, ignoring
+// order, duplicates and the expansion of write permissions. Lists containing a string that
+// cannot be parsed are compared as plain sets of strings.
+func Equal(a, b []string) bool {
+	pa, errA := ParseAll(a)
+	pb, errB := ParseAll(b)
+	if errA != nil || errB != nil {
+		a = slices.Clone(a)
+		b = slices.Clone(b)
+		slices.Sort(a)
+		slices.Sort(b)
+		return slices.Equal(slices.Compact(a), slices.Compact(b))
+	}
+	return slices.Equal(Normalise(pa), Normalise(pb))
+}
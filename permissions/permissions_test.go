@@ -0,0 +1,201 @@
+package permissions
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRoles(t *testing.T) {
+	tests := []struct {
+		role Role
+		want string
+	}{
+		{Any(), "any"},
+		{Guests(), "guests"},
+		{Users(), "users"},
+		{Users("verified"), "users/verified"},
+		{Users(""), "users"},
+		{User("5c1f88b4"), "user:5c1f88b4"},
+		{User("5c1f88b4", "unverified"), "user:5c1f88b4/unverified"},
+		{Team("admins"), "team:admins"},
+		{Team("admins", "owner"), "team:admins/owner"},
+		{Member("m1"), "member:m1"},
+		{Label("vip"), "label:vip"},
+	}
+	for _, tc := range tests {
+		if string(tc.role) != tc.want {
+			t.Errorf("got role %q, want %q", tc.role, tc.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	actions := []func(Role) Permission{Read, Create, Update, Delete, Write}
+	roles := []Role{
+		Any(), Guests(), Users(), Users("verified"), User("5c1f88b4"), User("5c1f88b4", "verified"),
+		Team("admins"), Team("admins", "owner"), Member("m1"), Label("vip"),
+	}
+	for _, action := range actions {
+		for _, role := range roles {
+			p := action(role)
+			s := p.String()
+			got, err := Parse(s)
+			if err != nil {
+				t.Errorf("Parse(%q): %v", s, err)
+				continue
+			}
+			if got != p {
+				t.Errorf("Parse(%q) = %+v, want %+v", s, got, p)
+			}
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		perm Permission
+		want string
+	}{
+		{Read(Any()), `read("any")`},
+		{Create(Users()), `create("users")`},
+		{Update(Team("admins", "owner")), `update("team:admins/owner")`},
+		{Delete(Member("m1")), `delete("member:m1")`},
+		{Write(User("u1", "verified")), `write("user:u1/verified")`},
+	}
+	for _, tc := range tests {
+		if got := tc.perm.String(); got != tc.want {
+			t.Errorf("got %s, want %s", got, tc.want)
+		}
+	}
+	got := Strings(Read(Any()), Delete(Label("vip")))
+	if want := []string{`read("any")`, `delete("label:vip")`}; !slices.Equal(got, want) {
+		t.Errorf("Strings: got %v, want %v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Permission
+		wantErr string
+	}{
+		{in: `read("any")`, want: Read(Any())},
+		{in: `  update( "team:admins/owner" ) `, want: Update(Team("admins", "owner"))},
+		{in: `delete("member:m1")`, want: Delete(Member("m1"))},
+		{in: `execute("any")`, wantErr: "unknown action"},
+		{in: `Read("any")`, wantErr: "unknown action"},
+		{in: `("any")`, wantErr: "unknown action"},
+		{in: `read("any)`, wantErr: "must be quoted"},
+		{in: `read(any")`, wantErr: "must be quoted"},
+		{in: `read(any)`, wantErr: "must be quoted"},
+		{in: `read(")`, wantErr: "must be quoted"},
+		{in: `read("any"`, wantErr: "expected action"},
+		{in: `read"any")`, wantErr: "expected action"},
+		{in: `read("")`, wantErr: "empty role"},
+		{in: ``, wantErr: "expected action"},
+	}
+	for _, tc := range tests {
+		got, err := Parse(tc.in)
+		switch {
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("Parse(%q): got error %v, want one containing %q", tc.in, err, tc.wantErr)
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("Parse(%q): %v", tc.in, err)
+		case tc.wantErr == "" && got != tc.want:
+			t.Errorf("Parse(%q) = %+v, want %+v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	got, err := ParseAll([]string{`read("any")`, `write("users")`})
+	if err != nil {
+		t.Fatalf("ParseAll: %v", err)
+	}
+	if want := []Permission{Read(Any()), Write(Users())}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := ParseAll([]string{`read("any")`, `bogus`}); err == nil {
+		t.Error("ParseAll with an invalid permission: got no error")
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Permission
+		want []Permission
+	}{
+		{"empty", nil, nil},
+		{
+			"write expands",
+			[]Permission{Write(Any())},
+			[]Permission{Create(Any()), Delete(Any()), Update(Any())},
+		},
+		{
+			"duplicates removed",
+			[]Permission{Read(Any()), Update(Users()), Read(Any()), Write(Users())},
+			[]Permission{Create(Users()), Delete(Users()), Read(Any()), Update(Users())},
+		},
+		{
+			"sorted",
+			[]Permission{Update(Team("b")), Read(Team("a"))},
+			[]Permission{Read(Team("a")), Update(Team("b"))},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Normalise(tc.in); !slices.Equal(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	have := []Permission{Read(Any()), Write(Users())}
+	want := []Permission{Read(Any()), Create(Users()), Update(Users()), Read(Team("admins"))}
+	missing, extra := Diff(have, want)
+	if w := []Permission{Read(Team("admins"))}; !slices.Equal(missing, w) {
+		t.Errorf("missing: got %v, want %v", missing, w)
+	}
+	if w := []Permission{Delete(Users())}; !slices.Equal(extra, w) {
+		t.Errorf("extra: got %v, want %v", extra, w)
+	}
+	missing, extra = Diff([]Permission{Write(Any())}, []Permission{Create(Any()), Update(Any()), Delete(Any())})
+	if missing != nil || extra != nil {
+		t.Errorf("write against its expansion: got missing %v, extra %v, want none", missing, extra)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want bool
+	}{
+		{"identical", []string{`read("any")`}, []string{`read("any")`}, true},
+		{"order ignored", []string{`read("any")`, `update("users")`}, []string{`update("users")`, `read("any")`}, true},
+		{"duplicates ignored", []string{`read("any")`, `read("any")`}, []string{`read("any")`}, true},
+		{
+			"write matches its expansion",
+			[]string{`write("team:admins/owner")`},
+			[]string{`create("team:admins/owner")`, `update("team:admins/owner")`, `delete("team:admins/owner")`},
+			true,
+		},
+		{"write differs from part of its expansion", []string{`write("users")`}, []string{`create("users")`, `update("users")`}, false},
+		{"different role", []string{`read("users")`}, []string{`read("users/verified")`}, false},
+		{"different action", []string{`read("any")`}, []string{`update("any")`}, false},
+		{"both empty", nil, []string{}, true},
+		{"unparseable compared as strings", []string{`bogus`, `read("any")`}, []string{`read("any")`, `bogus`}, true},
+		{"unparseable differs", []string{`bogus`}, []string{`read("any")`}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Equal(tc.a, tc.b); got != tc.want {
+				t.Errorf("Equal(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
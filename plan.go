@@ -0,0 +1,357 @@
+package appres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Action describes what Apply would do with a single resource.
+type Action string
+
+const (
+	// ActionCreate means the resource does not exist and would be created
+	ActionCreate Action = "create"
+
+	// ActionUpdate means the resource exists but differs in settings that can be updated in place
+	ActionUpdate Action = "update"
+
+	// ActionSkip means a matching resource already exists and would be left untouched
+	ActionSkip Action = "skip"
+
+	// ActionConflict means a resource with the same name or key exists but differs from the schema
+	// in a way Apply cannot reconcile
+	ActionConflict Action = "conflict"
+)
+
+// Change is a single entry of a ChangeSet.
+type Change struct {
+	// Kind is the resource kind: "database", "collection", "attribute", "index" or "bucket"
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Path identifies the resource within the schema, e.g. "my-database/users/username"
+	Path string `json:"path" yaml:"path"`
+
+	// ID is the ID of the existing resource, empty when the resource would be created
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Action is what Apply would do with the resource
+	Action Action `json:"action" yaml:"action"`
+
+	// Detail explains an update or conflict in human readable form
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+// ChangeSet is the result of Plan. It lists every resource in a schema together with
+// the action Apply would take for it, grouped for review: each database is followed by its
+// collections in schema order, each collection by its attributes and indexes, and the buckets
+// come last. Apply processes a database in another order: all of its collections first, then
+// their attributes, with relationships after all other attributes, and then their indexes.
+type ChangeSet struct {
+	Changes []Change `json:"changes" yaml:"changes"`
+}
+
+// HasConflicts reports whether any change in the set is a conflict.
+func (cs *ChangeSet) HasConflicts() bool {
+	for _, c := range cs.Changes {
+		if c.Action == ActionConflict {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of changes with the given action.
+func (cs *ChangeSet) Count(action Action) int {
+	n := 0
+	for _, c := range cs.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// String renders the change set for review, one resource per line.
+// Lines are prefixed with "+" for create, "~" for update, "=" for skip and "!" for conflict.
+func (cs *ChangeSet) String() string {
+	var b strings.Builder
+	for _, c := range cs.Changes {
+		prefix := "="
+		switch c.Action {
+		case ActionCreate:
+			prefix = "+"
+		case ActionUpdate:
+			prefix = "~"
+		case ActionConflict:
+			prefix = "!"
+		}
+		fmt.Fprintf(&b, "%s %s %s", prefix, c.Kind, c.Path)
+		if c.ID != "" {
+			fmt.Fprintf(&b, " (id: %s)", c.ID)
+		}
+		if c.Detail != "" {
+			fmt.Fprintf(&b, ": %s", c.Detail)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d to create, %d to update, %d unchanged, %d conflicts\n",
+		cs.Count(ActionCreate), cs.Count(ActionUpdate), cs.Count(ActionSkip), cs.Count(ActionConflict))
+	return b.String()
+}
+
+// Plan reports what Apply would do with the schema without changing anything in Appwrite.
+// Existing resources are matched the same way the create functions match them:
+// databases, collections and buckets by ID and then by name, attributes and indexes by key. Attributes that differ in
+// settings UpdateAttribute can change are reported as updates, other differences as conflicts.
+// Indexes that differ are conflicts too, since Apply does not recreate them; use PlanWithOptions
+// to plan ApplyWithOptions.
+// A database, collection or bucket whose ID is set in the schema but only matches an
+// existing one by name, with another ID, is a conflict as well, since Apply would fail on
+// it with an *IDMismatchError.
+//
+// Parameters:
+//   - schema: The schema to plan, typically loaded with LoadSchema
+//
+// Returns:
+//   - *ChangeSet: Every resource in the schema with its planned action
+//   - error: The problems found by Schema.Validate, or any error that occurred while listing
+//     existing resources
+//
+// Example:
+//
+//	changes, err := client.Plan(schema)
+//	if err != nil {
+//		log.Fatal("Failed to plan schema:", err)
+//	}
+//	fmt.Print(changes)
+//	if !changes.HasConflicts() {
+//		err = client.Apply(schema)
+//	}
+func (c *Client) Plan(schema *Schema) (*ChangeSet, error) {
+	return c.PlanWithOptions(schema, ApplyOptions{})
+}
+
+// PlanWithOptions reports what ApplyWithOptions would do with the schema and opts. With
+// RecreateIndexes set, indexes that differ are reported as updates instead of conflicts.
+//
+// Parameters:
+//   - schema: The schema to plan, typically loaded with LoadSchema
+//   - opts: The ApplyOptions the schema would be applied with
+//
+// Returns:
+//   - *ChangeSet: Every resource in the schema with its planned action
+//   - error: The problems found by Schema.Validate, or any error that occurred while listing
+//     existing resources
+func (c *Client) PlanWithOptions(schema *Schema, opts ApplyOptions) (*ChangeSet, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	cs := &ChangeSet{}
+	databases, err := c.listDatabases()
+	if err != nil {
+		return nil, err
+	}
+	for _, dbDef := range schema.Databases {
+		existing := matchDatabase(databases, dbDef.ID, dbDef.Name)
+		if existing == nil {
+			cs.Changes = append(cs.Changes, Change{Kind: "database", Path: dbDef.Name, ID: dbDef.ID, Action: ActionCreate})
+			for _, colDef := range dbDef.Collections {
+				planNewCollection(cs, dbDef.Name, colDef)
+			}
+			continue
+		}
+		dbChange := Change{Kind: "database", Path: dbDef.Name, ID: existing.Id, Action: ActionSkip}
+		if detail := idConflict(dbDef.ID, existing.Id); detail != "" {
+			dbChange.Action = ActionConflict
+			dbChange.Detail = detail
+		}
+		cs.Changes = append(cs.Changes, dbChange)
+		if err := c.planCollections(cs, existing.Id, dbDef, opts); err != nil {
+			return nil, err
+		}
+	}
+	if len(schema.Buckets) > 0 {
+		buckets, err := c.listBuckets()
+		if err != nil {
+			return nil, err
+		}
+		for _, buc := range schema.Buckets {
+			change := Change{Kind: "bucket", Path: buc.Name, ID: buc.ID, Action: ActionCreate}
+			if b := matchBucket(buckets, buc.ID, buc.Name); b != nil {
+				change.ID = b.Id
+				change.Action = ActionSkip
+				if drift := DiffBucket(*b, buc); len(drift) > 0 {
+					change.Action = ActionUpdate
+					change.Detail = formatDrift(drift)
+				}
+				if detail := idConflict(buc.ID, b.Id); detail != "" {
+					change.Action = ActionConflict
+					change.Detail = detail
+				}
+			}
+			cs.Changes = append(cs.Changes, change)
+		}
+	}
+	return cs, nil
+}
+
+// Plan plans a schema using the default client initialised by Utils().
+// See Client.Plan for details.
+func Plan(schema *Schema) (*ChangeSet, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.Plan(schema)
+}
+
+// PlanContext is like Plan but sends its requests with ctx.
+func (c *Client) PlanContext(ctx context.Context, schema *Schema) (*ChangeSet, error) {
+	return c.withContext(ctx).Plan(schema)
+}
+
+// PlanContext is like Plan but honours ctx, using the default client initialised by Utils().
+// See Client.PlanContext for details.
+func PlanContext(ctx context.Context, schema *Schema) (*ChangeSet, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.PlanContext(ctx, schema)
+}
+
+// PlanWithOptions plans a schema using the default client initialised by Utils().
+// See Client.PlanWithOptions for details.
+func PlanWithOptions(schema *Schema, opts ApplyOptions) (*ChangeSet, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.PlanWithOptions(schema, opts)
+}
+
+// PlanWithOptionsContext is like PlanWithOptions but sends its requests with ctx.
+func (c *Client) PlanWithOptionsContext(ctx context.Context, schema *Schema, opts ApplyOptions) (*ChangeSet, error) {
+	return c.withContext(ctx).PlanWithOptions(schema, opts)
+}
+
+// PlanWithOptionsContext is like PlanWithOptions but honours ctx, using the default client initialised by Utils().
+// See Client.PlanWithOptionsContext for details.
+func PlanWithOptionsContext(ctx context.Context, schema *Schema, opts ApplyOptions) (*ChangeSet, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.PlanWithOptionsContext(ctx, schema, opts)
+}
+
+// planCollections adds the changes for the collections of an existing database.
+func (c *Client) planCollections(cs *ChangeSet, dbID string, dbDef DatabaseType, opts ApplyOptions) error {
+	collections, err := c.listCollections(dbID)
+	if err != nil {
+		return err
+	}
+	for _, colDef := range dbDef.Collections {
+		existing := matchCollection(collections, colDef.ID, colDef.Name)
+		if existing == nil {
+			planNewCollection(cs, dbDef.Name, colDef)
+			continue
+		}
+		colPath := dbDef.Name + "/" + colDef.Name
+		colChange := Change{Kind: "collection", Path: colPath, ID: existing.Id, Action: ActionSkip}
+		if drift := DiffCollection(*existing, colDef); len(drift) > 0 {
+			colChange.Action = ActionUpdate
+			colChange.Detail = formatDrift(drift)
+		}
+		if detail := idConflict(colDef.ID, existing.Id); detail != "" {
+			colChange.Action = ActionConflict
+			colChange.Detail = detail
+		}
+		cs.Changes = append(cs.Changes, colChange)
+		attributes, err := c.listAttributes(dbID, existing.Id)
+		if err != nil {
+			return err
+		}
+		for _, att := range colDef.Attributes {
+			if att.Type == "relationship" {
+				resolved, ok := resolveRelated(collections, existing.Id, att)
+				if !ok {
+					change := Change{Kind: "attribute", Path: colPath + "/" + att.key(), Action: ActionCreate,
+						Detail: fmt.Sprintf("related collection %q will be created first", relatedName(att))}
+					if schemaCollection(dbDef, att.RelatedCollectionID, att.RelatedCollection) < 0 {
+						change.Action = ActionConflict
+						change.Detail = fmt.Sprintf("related collection %q not found in the database or the schema", relatedName(att))
+					}
+					cs.Changes = append(cs.Changes, change)
+					continue
+				}
+				att = resolved
+			}
+			change := Change{Kind: "attribute", Path: colPath + "/" + att.Name, Action: ActionCreate}
+			for _, attr := range attributes {
+				if attrName, ok := attr["key"].(string); ok && attrName == att.Name {
+					change.Action = ActionSkip
+					if drift := DiffAttribute(attr, att); len(drift) > 0 {
+						change.Action = ActionUpdate
+						change.Detail = formatDrift(drift)
+						if fixed := immutableDrift(drift); len(fixed) > 0 {
+							change.Action = ActionConflict
+							change.Detail = formatDrift(fixed)
+						}
+					}
+					break
+				}
+			}
+			cs.Changes = append(cs.Changes, change)
+		}
+		if len(colDef.Indexes) == 0 {
+			continue
+		}
+		indexes, err := c.listIndexes(dbID, existing.Id)
+		if err != nil {
+			return err
+		}
+		for _, idx := range colDef.Indexes {
+			change := Change{Kind: "index", Path: colPath + "/" + idx.Key, Action: ActionCreate}
+			for _, live := range indexes {
+				if live.Key == idx.Key {
+					change.Action = ActionSkip
+					if drift := diffIndex(live, idx); len(drift) > 0 {
+						change.Action = ActionConflict
+						change.Detail = formatDrift(drift) + " (indexes cannot be updated; recreate it to apply)"
+						if opts.RecreateIndexes {
+							change.Action = ActionUpdate
+							change.Detail = formatDrift(drift) + " (index will be recreated)"
+						}
+					}
+					break
+				}
+			}
+			cs.Changes = append(cs.Changes, change)
+		}
+	}
+	return nil
+}
+
+// planNewCollection adds create changes for a collection that does not exist yet,
+// together with all of its attributes and indexes.
+func planNewCollection(cs *ChangeSet, dbName string, colDef CollectionType) {
+	colPath := dbName + "/" + colDef.Name
+	cs.Changes = append(cs.Changes, Change{Kind: "collection", Path: colPath, ID: colDef.ID, Action: ActionCreate})
+	for _, att := range colDef.Attributes {
+		cs.Changes = append(cs.Changes, Change{Kind: "attribute", Path: colPath + "/" + att.key(), Action: ActionCreate})
+	}
+	for _, idx := range colDef.Indexes {
+		cs.Changes = append(cs.Changes, Change{Kind: "index", Path: colPath + "/" + idx.Key, Action: ActionCreate})
+	}
+}
+
+// idConflict describes the conflict of a resource requested with id that was matched by name
+// to an existing resource with another ID, or returns "" if there is none.
+func idConflict(id string, existingID string) string {
+	if id == "" || id == existingID {
+		return ""
+	}
+	return fmt.Sprintf("exists with ID %q, the schema sets %q", existingID, id)
+}
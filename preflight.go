@@ -0,0 +1,202 @@
+package appres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/appwrite/sdk-for-go/client"
+)
+
+// preflightProbeID is a database and collection ID that is not expected to exist.
+// Read probes against it return "not found" when the scope is granted.
+const preflightProbeID = "appres-preflight"
+
+// preflightInvalidID is rejected by Appwrite's ID validation, so write probes using it
+// fail with a validation error when the scope is granted and never create anything.
+const preflightInvalidID = "!appres-preflight"
+
+// ScopeCheck is the result of checking a single API key scope.
+type ScopeCheck struct {
+	// Scope is the Appwrite scope, e.g. "databases.read"
+	Scope string `json:"scope" yaml:"scope"`
+
+	// Granted reports whether the API key has the scope
+	Granted bool `json:"granted" yaml:"granted"`
+
+	// Detail holds the Appwrite error message when the scope is missing or could not be checked
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+// PreflightReport is the result of Preflight.
+type PreflightReport struct {
+	// Endpoint is the Appwrite endpoint that was checked
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Reachable reports whether the endpoint answered its health check
+	Reachable bool `json:"reachable" yaml:"reachable"`
+
+	// ProjectValid reports whether Appwrite recognised the project ID
+	ProjectValid bool `json:"projectValid" yaml:"projectValid"`
+
+	// Scopes lists the result for every scope appres needs
+	Scopes []ScopeCheck `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// MissingScopes returns the scopes the API key does not have.
+func (r *PreflightReport) MissingScopes() []string {
+	var missing []string
+	for _, s := range r.Scopes {
+		if !s.Granted {
+			missing = append(missing, s.Scope)
+		}
+	}
+	return missing
+}
+
+// OK reports whether the endpoint is reachable, the project is valid and every scope is granted.
+func (r *PreflightReport) OK() bool {
+	return r.Reachable && r.ProjectValid && len(r.MissingScopes()) == 0
+}
+
+// Preflight checks that the client can provision resources before a long run starts.
+// It verifies the endpoint is reachable, the project ID is valid and the API key has the
+// database, collection, attribute, index and bucket scopes appres uses.
+//
+// Every check is a cheap call that cannot change anything: read scopes are probed with list
+// requests, and write scopes with create requests using an ID that Appwrite rejects as
+// invalid. Appwrite checks scopes before validating parameters, so a validation error means
+// the scope is granted while an authorisation error means it is missing.
+//
+// Returns:
+//   - *PreflightReport: The result of every check
+//   - error: Any unexpected error that prevented the checks from completing
+//
+// Example:
+//
+//	report, err := client.Preflight()
+//	if err != nil {
+//		log.Fatal("Preflight failed:", err)
+//	}
+//	if !report.OK() {
+//		log.Fatal("API key is missing scopes: ", report.MissingScopes())
+//	}
+func (c *Client) Preflight() (*PreflightReport, error) {
+	report := &PreflightReport{Endpoint: c.appwrite.Endpoint}
+	if err := ping(c.context(), c.appwrite.Endpoint); err != nil {
+		if ctxErr := c.context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return report, nil
+	}
+	report.Reachable = true
+	report.ProjectValid = true
+
+	probes := []struct {
+		scope string
+		call  func() error
+	}{
+		{"databases.read", func() error {
+			_, err := c.databases.List()
+			return err
+		}},
+		{"databases.write", func() error {
+			_, err := c.databases.Create(preflightInvalidID, "appres preflight")
+			return err
+		}},
+		{"collections.read", func() error {
+			_, err := c.databases.ListCollections(preflightProbeID)
+			return err
+		}},
+		{"collections.write", func() error {
+			_, err := c.databases.CreateCollection(preflightProbeID, preflightInvalidID, "appres preflight")
+			return err
+		}},
+		{"attributes.read", func() error {
+			_, err := c.databases.ListAttributes(preflightProbeID, preflightProbeID)
+			return err
+		}},
+		{"attributes.write", func() error {
+			_, err := c.databases.CreateBooleanAttribute(preflightProbeID, preflightProbeID, preflightInvalidID, false)
+			return err
+		}},
+		{"indexes.read", func() error {
+			_, err := c.databases.ListIndexes(preflightProbeID, preflightProbeID)
+			return err
+		}},
+		{"indexes.write", func() error {
+			_, err := c.databases.CreateIndex(preflightProbeID, preflightProbeID, preflightInvalidID, "key", []string{preflightProbeID})
+			return err
+		}},
+		{"buckets.read", func() error {
+			_, err := c.storage.ListBuckets()
+			return err
+		}},
+		{"buckets.write", func() error {
+			_, err := c.storage.CreateBucket(preflightInvalidID, "appres preflight")
+			return err
+		}},
+	}
+	for _, probe := range probes {
+		check := ScopeCheck{Scope: probe.scope}
+		err := probe.call()
+		var apiErr *client.AppwriteError
+		switch {
+		case err == nil:
+			check.Granted = true
+		case !errors.As(err, &apiErr):
+			return nil, err
+		case errorType(apiErr) == "project_not_found":
+			report.ProjectValid = false
+			check.Detail = apiErr.GetMessage()
+		case apiErr.GetStatusCode() == http.StatusUnauthorized || apiErr.GetStatusCode() == http.StatusForbidden:
+			check.Detail = apiErr.GetMessage()
+		case apiErr.GetStatusCode() < http.StatusInternalServerError:
+			// Validation and not found errors are only reported once the scope check has passed
+			check.Granted = true
+		default:
+			check.Detail = apiErr.GetMessage()
+		}
+		report.Scopes = append(report.Scopes, check)
+	}
+	return report, nil
+}
+
+// Preflight checks connectivity and API key scopes using the default client initialised by Utils().
+// See Client.Preflight for details.
+func Preflight() (*PreflightReport, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.Preflight()
+}
+
+// PreflightContext is like Preflight but sends its requests with ctx. If ctx ends before the
+// checks complete, the context's error is returned instead of a report.
+func (c *Client) PreflightContext(ctx context.Context) (*PreflightReport, error) {
+	return c.withContext(ctx).Preflight()
+}
+
+// PreflightContext is like Preflight but honours ctx, using the default client initialised by Utils().
+// See Client.PreflightContext for details.
+func PreflightContext(ctx context.Context) (*PreflightReport, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.PreflightContext(ctx)
+}
+
+// errorType returns the machine readable error type Appwrite includes in error responses,
+// e.g. "project_not_found", or an empty string if there is none.
+func errorType(err *client.AppwriteError) string {
+	var body struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal([]byte(err.GetResponse()), &body) != nil {
+		return ""
+	}
+	return body.Type
+}
@@ -0,0 +1,76 @@
+package appres
+
+import (
+	"fmt"
+
+	"github.com/appwrite/sdk-for-go/models"
+)
+
+// key returns the key the attribute is stored under. Relationship attributes without a
+// Name are stored under the ID of the related collection, as Appwrite does.
+func (a AttributeType) key() string {
+	if a.Name != "" || a.Type != "relationship" {
+		return a.Name
+	}
+	if a.RelatedCollectionID != "" {
+		return a.RelatedCollectionID
+	}
+	return a.RelatedCollection
+}
+
+// resolveRelationship looks up the related collection of a relationship attribute in the
+// database and returns the attribute with the defaults Appwrite applies filled in: the
+// related collection ID, the key, and for two-way relationships the key on the related
+// collection, which defaults to the ID of the collection holding the attribute.
+func (c *Client) resolveRelationship(dbID string, colID string, att AttributeType) (AttributeType, error) {
+	collections, err := c.listCollections(dbID)
+	if err != nil {
+		return att, err
+	}
+	resolved, ok := resolveRelated(collections, colID, att)
+	if !ok {
+		return att, fmt.Errorf("relationship %q: related collection %q not found", att.key(), relatedName(att))
+	}
+	return resolved, nil
+}
+
+// resolveRelated resolves a relationship attribute against the collections of its database.
+// It reports false if the related collection does not exist.
+func resolveRelated(collections []models.Collection, colID string, att AttributeType) (AttributeType, bool) {
+	related := matchCollection(collections, att.RelatedCollectionID, att.RelatedCollection)
+	if related == nil {
+		return att, false
+	}
+	att.RelatedCollectionID = related.Id
+	if att.Name == "" {
+		att.Name = related.Id
+	}
+	if att.TwoWay && att.TwoWayKey == "" {
+		att.TwoWayKey = colID
+	}
+	return att, true
+}
+
+// relatedName returns the ID or name the related collection was requested by.
+func relatedName(att AttributeType) string {
+	if att.RelatedCollectionID != "" {
+		return att.RelatedCollectionID
+	}
+	return att.RelatedCollection
+}
+
+// checkTwoWayKey makes sure the key a new two-way relationship adds to the related collection
+// is not already taken there, so a clash is reported before the relationship is created.
+func (c *Client) checkTwoWayKey(dbID string, att AttributeType) error {
+	attributes, err := c.listAttributes(dbID, att.RelatedCollectionID)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attributes {
+		if key, ok := attr["key"].(string); ok && key == att.TwoWayKey {
+			return fmt.Errorf("relationship %q: two-way key %q already exists on related collection %q",
+				att.Name, att.TwoWayKey, att.RelatedCollectionID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package appres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/appwrite/sdk-for-go/client"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+//
+// A request is retried when Appwrite answers with one of RetryableStatusCodes, or when no
+// answer is received at all, e.g. because the connection was reset. The delay before the
+// n-th retry is InitialBackoff doubled n-1 times, capped at MaxBackoff and varied by Jitter.
+// If the response carries a Retry-After header asking for a longer delay, that delay is used
+// instead.
+//
+// Retrying a request that creates a resource is safe: every ID is chosen before the first
+// attempt, so a retry can never create a second resource. If an attempt that appeared to
+// fail did create the resource, the retry is answered with 409 Conflict and the create
+// functions look the resource up again, just as they would have found it before creating it.
+//
+// Example:
+//
+//	client, err := appres.NewClient(
+//		appres.WithConfig(cfg),
+//		appres.WithRetry(appres.RetryPolicy{
+//			MaxAttempts:          6,
+//			InitialBackoff:       time.Second,
+//			MaxBackoff:           30 * time.Second,
+//			Jitter:               0.2,
+//			RetryableStatusCodes: []int{429, 502, 503, 504},
+//		}),
+//	)
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent, including the first attempt.
+	// One or less disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts; zero means no cap
+	MaxBackoff time.Duration
+
+	// Jitter varies each delay randomly by up to this fraction of it, e.g. 0.2 for ±20%,
+	// so clients that failed together do not retry together
+	Jitter float64
+
+	// RetryableStatusCodes lists the HTTP status codes that are worth retrying
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy is used by clients created without WithRetry. It makes up to four
+// attempts, waiting about half a second, one second and two seconds between them, and
+// retries rate limiting (429) and server errors (500, 502, 503, 504).
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:          4,
+	InitialBackoff:       500 * time.Millisecond,
+	MaxBackoff:           10 * time.Second,
+	Jitter:               0.2,
+	RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+}
+
+// NoRetry disables retries when passed to WithRetry.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// WithRetry sets the policy for retrying requests that fail with a transient error.
+// Clients use DefaultRetryPolicy unless this option is given; pass NoRetry to send every
+// request only once.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = &policy
+	}
+}
+
+// backoff returns the delay before the given retry, counting from 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(2, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// retryTransport sends requests through base, retrying them according to policy. Each
+// attempt is limited by timeout, so slow attempts do not use up the time left for retries.
+type retryTransport struct {
+	policy  RetryPolicy
+	timeout time.Duration
+	base    http.RoundTripper
+	logger  *slog.Logger
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+		resp, err := t.send(r)
+		retry := attempt < t.policy.MaxAttempts && req.Context().Err() == nil &&
+			(req.Body == nil || req.GetBody != nil)
+		if err != nil {
+			retry = retry && !errors.Is(err, context.Canceled)
+		} else {
+			retry = retry && slices.Contains(t.policy.RetryableStatusCodes, resp.StatusCode)
+		}
+		if !retry {
+			return resp, err
+		}
+
+		delay := t.policy.backoff(attempt)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok && after > delay {
+				delay = after
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		t.logger.WarnContext(req.Context(), "Retrying request",
+			"action", "retry", "method", req.Method, "path", req.URL.Path, "attempt", attempt,
+			"delay", delay.Round(time.Millisecond), "error", reason)
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send makes a single attempt, limited by the transport's timeout. The timeout keeps
+// running while the response body is read and is released when the body is closed.
+func (t *retryTransport) send(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases the timeout of an attempt once its response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// isConflict reports whether err is Appwrite's 409 Conflict answer to creating a resource
+// whose ID or key is already taken. After a retry this usually means an earlier attempt
+// created the resource even though its response was lost.
+func isConflict(err error) bool {
+	var apiErr *client.AppwriteError
+	return errors.As(err, &apiErr) && apiErr.GetStatusCode() == http.StatusConflict
+}
+
+// recoverConflict handles a create request of the given kind of resource that failed with
+// err, a 409 Conflict. The resource is looked up again with lookup, which must do what the
+// create function does for a resource it finds before creating it: report whether the
+// resource exists, with the error to return for it, such as a drift error. fields are added
+// to the log records, e.g. the collection holding an attribute.
+//
+// If the resource is found, lookup's error is returned. Otherwise the failure is logged and
+// err is returned, together with the lookup's error if looking the resource up failed.
+func (c *Client) recoverConflict(kind string, name string, err error, lookup func() (bool, error), fields ...any) error {
+	c.logger.DebugContext(c.context(), "Conflict creating "+kind+", looking it up again",
+		append([]any{"kind", kind, "name", name, "action", "create", "error", err}, fields...)...)
+	found, lookupErr := lookup()
+	if found {
+		return lookupErr
+	}
+	if lookupErr != nil {
+		err = fmt.Errorf("%w; looking the %s up again failed: %w", err, kind, lookupErr)
+	}
+	c.logger.ErrorContext(c.context(), "Error creating "+kind,
+		append([]any{"kind", kind, "name", name, "action", "create", "error", err}, fields...)...)
+	return err
+}
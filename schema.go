@@ -0,0 +1,205 @@
+package appres
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a declarative description of the Appwrite resources a project needs.
+// It is usually kept in version control as a YAML or JSON document, loaded with
+// LoadSchema and created with Apply.
+//
+// Example document (YAML):
+//
+//	databases:
+//	  - name: my-app-database
+//	    collections:
+//	      - name: users
+//	        attributes:
+//	          - type: string
+//	            name: username
+//	            size: 50
+//	            required: true
+//	          - type: integer
+//	            name: age
+//	            min: 0
+//	            max: 120
+//	buckets:
+//	  - name: user-uploads
+//	    enabled: true
+//	    fileSecurity: true
+//	    maxFileSize: 10000000
+//
+// The same structure can be written as JSON using identical keys.
+type Schema struct {
+	// Databases lists the databases to create, each with its collections and attributes
+	Databases []DatabaseType `json:"databases,omitempty" yaml:"databases,omitempty"`
+
+	// Buckets lists the storage buckets to create
+	Buckets []BucketType `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+}
+
+// LoadSchema reads a schema document from disk.
+// The format is chosen from the file extension: ".json" is decoded as JSON,
+// ".yaml" and ".yml" as YAML.
+//
+// Parameters:
+//   - path: The path to the schema document
+//
+// Returns:
+//   - *Schema: The decoded schema
+//   - error: Any error that occurred while reading or decoding the document
+//
+// Example:
+//
+//	schema, err := appres.LoadSchema("schema.yaml")
+//	if err != nil {
+//		log.Fatal("Failed to load schema:", err)
+//	}
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format, err := formatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := ParseSchema(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// ParseSchema decodes a schema document held in memory.
+//
+// Parameters:
+//   - data: The raw schema document
+//   - format: The document format, either "yaml" or "json"
+//
+// Returns:
+//   - *Schema: The decoded schema
+//   - error: Any error that occurred while decoding the document
+func ParseSchema(data []byte, format string) (*Schema, error) {
+	var schema Schema
+	switch format {
+	case "json":
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, err
+		}
+	case "yaml":
+		if err := yaml.Unmarshal(data, &schema); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported schema format: %s", format)
+	}
+	schema.normalise()
+	return &schema, nil
+}
+
+// SaveSchema writes a schema document to disk, choosing the format from the file extension
+// in the same way as LoadSchema.
+//
+// Parameters:
+//   - path: The path to write the schema document to
+//   - schema: The schema to write, typically created with Export
+//
+// Returns:
+//   - error: Any error that occurred while encoding or writing the document
+//
+// Example:
+//
+//	schema, err := client.Export()
+//	if err != nil {
+//		log.Fatal("Failed to export project:", err)
+//	}
+//	if err := appres.SaveSchema("schema.yaml", schema); err != nil {
+//		log.Fatal("Failed to save schema:", err)
+//	}
+func SaveSchema(path string, schema *Schema) error {
+	format, err := formatFromPath(path)
+	if err != nil {
+		return err
+	}
+	data, err := schema.Marshal(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Marshal encodes the schema as a document that ParseSchema can read back.
+//
+// Parameters:
+//   - format: The document format, either "yaml" or "json"
+//
+// Returns:
+//   - []byte: The encoded schema document
+//   - error: Any error that occurred while encoding the schema
+func (s *Schema) Marshal(format string) ([]byte, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case "yaml":
+		return yaml.Marshal(s)
+	default:
+		return nil, fmt.Errorf("unsupported schema format: %s", format)
+	}
+}
+
+// formatFromPath returns the schema format for a file extension.
+func formatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	}
+	return "", fmt.Errorf("unsupported schema file extension: %s", filepath.Ext(path))
+}
+
+// normalise converts decoded values into the Go types CreateAttribute expects.
+// JSON decodes every number as float64, so whole numbers on integer attributes
+// are converted back to int. YAML decodes an unquoted timestamp as time.Time, so
+// defaults of datetime attributes are converted back to RFC3339 strings.
+func (s *Schema) normalise() {
+	for i := range s.Databases {
+		for j := range s.Databases[i].Collections {
+			attrs := s.Databases[i].Collections[j].Attributes
+			for k := range attrs {
+				switch attrs[k].Type {
+				case "integer":
+					attrs[k].Default = wholeNumberToInt(attrs[k].Default)
+					attrs[k].Min = wholeNumberToInt(attrs[k].Min)
+					attrs[k].Max = wholeNumberToInt(attrs[k].Max)
+				case "datetime":
+					if t, ok := attrs[k].Default.(time.Time); ok {
+						attrs[k].Default = t.Format(time.RFC3339Nano)
+					}
+				}
+			}
+		}
+	}
+}
+
+// wholeNumberToInt returns v as an int when it is a float64 without a fractional part.
+// Any other value is returned unchanged.
+func wholeNumberToInt(v interface{}) interface{} {
+	if f, ok := v.(float64); ok && f == math.Trunc(f) {
+		return int(f)
+	}
+	return v
+}
@@ -1,66 +1,418 @@
 package appres
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
 
-	"github.com/appwrite/sdk-for-go/id"
 	"github.com/appwrite/sdk-for-go/models"
 	"github.com/appwrite/sdk-for-go/storage"
+
+	"github.com/Haepapa/appres/permissions"
 )
 
-// CreateBucket creates a new storage bucket with the specified configuration.
+// CreateBucket creates a new storage bucket with the specified configuration or returns the
+// existing one if a bucket with the same ID or name already exists.
 // It creates a bucket with customizable security, file size limits, and permissions.
 //
+// An existing bucket is compared with buc using DiffBucket. A matching bucket is returned
+// as is; a mismatch is reported as a *BucketDriftError listing every differing setting.
+// Use EnsureBucket to update the bucket instead.
+//
+// The bucket is created with buc.ID when set, or a generated unique ID otherwise.
+//
+// The bucket is checked with BucketType.Validate before any request is made.
+// MaxFileSize is left to Appwrite's default when zero, and FileSecurity, Enabled, Encryption
+// and Antivirus when nil.
+//
 // Parameters:
 //   - buc: BucketType struct containing the bucket configuration
 //
-// Global Variables Used:
-//   - AppwriteStorage: The initialized Appwrite storage client
-//
 // Returns:
-//   - *models.Bucket: Pointer to the created bucket
-//   - error: Any error that occurred during the operation
+//   - *models.Bucket: Pointer to the created or existing bucket
+//   - error: Any error that occurred during the operation, a validation error,
+//     *BucketDriftError if an existing bucket differs, or *IDMismatchError if buc.ID is set
+//     and the bucket with the name has another ID
 //
 // Example:
 //
 //	bucket := appres.BucketType{
 //		Name:         "my-bucket",
-//		Enabled:      true,
-//		FileSecurity: true,
+//		FileSecurity: appres.Ptr(true),
 //		MaxFileSize:  10000000, // 10MB
 //		Permissions:  []string{"read(\"any\")"},
 //	}
-//	buc, err := appres.CreateBucket(bucket)
+//	buc, err := client.CreateBucket(bucket)
 //	if err != nil {
 //		log.Fatal("Failed to create bucket:", err)
 //	}
-func CreateBucket(buc BucketType) (*models.Bucket, error) {
+func (c *Client) CreateBucket(buc BucketType) (*models.Bucket, error) {
+	if err := buc.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := c.existingBucket(buc)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
 
 	var opts []storage.CreateBucketOption
-
-	opts = append(opts, AppwriteStorage.WithCreateBucketAntivirus(buc.FileSecurity))
-	opts = append(opts, AppwriteStorage.WithCreateBucketAntivirus(buc.Enabled))
-	opts = append(opts, AppwriteStorage.WithCreateBucketAntivirus(buc.Antivirus))
-	opts = append(opts, AppwriteStorage.WithCreateBucketAntivirus(buc.Encryption))
-	if buc.MaxFileSize < 0 || buc.MaxFileSize > 30000000 {
-		return nil,  errors.New("MaxFileSize must be between 0 and 30MB")
-	} else {
-		opts = append(opts, AppwriteStorage.WithCreateBucketMaximumFileSize(buc.MaxFileSize))
+	if buc.FileSecurity != nil {
+		opts = append(opts, c.storage.WithCreateBucketFileSecurity(*buc.FileSecurity))
+	}
+	if buc.Enabled != nil {
+		opts = append(opts, c.storage.WithCreateBucketEnabled(*buc.Enabled))
+	}
+	if buc.Encryption != nil {
+		opts = append(opts, c.storage.WithCreateBucketEncryption(*buc.Encryption))
+	}
+	if buc.Antivirus != nil {
+		opts = append(opts, c.storage.WithCreateBucketAntivirus(*buc.Antivirus))
+	}
+	if buc.MaxFileSize > 0 {
+		opts = append(opts, c.storage.WithCreateBucketMaximumFileSize(buc.MaxFileSize))
 	}
 	if buc.Permissions != nil {
-		opts = append(opts, AppwriteStorage.WithCreateBucketPermissions(buc.Permissions))
+		opts = append(opts, c.storage.WithCreateBucketPermissions(buc.Permissions))
 	}
 	if buc.AllowedFileExtensions != nil {
-		opts = append(opts, AppwriteStorage.WithCreateBucketAllowedFileExtensions(buc.AllowedFileExtensions))
+		opts = append(opts, c.storage.WithCreateBucketAllowedFileExtensions(buc.AllowedFileExtensions))
 	}
 	if buc.Compression != "" {
-		opts = append(opts, AppwriteStorage.WithCreateBucketCompression(buc.Compression))
+		opts = append(opts, c.storage.WithCreateBucketCompression(buc.Compression))
 	}
 
-
-	return AppwriteStorage.CreateBucket(
-        id.Unique(),
-        buc.Name,
+	start := time.Now()
+	created, err := c.storage.CreateBucket(
+		idOrUnique(buc.ID),
+		buc.Name,
 		opts...,
-    )
-}
\ No newline at end of file
+	)
+	if isConflict(err) {
+		if err := c.recoverConflict("bucket", buc.Name, err, func() (found bool, err error) {
+			existing, err = c.existingBucket(buc)
+			return existing != nil, err
+		}, "id", buc.ID); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error creating bucket",
+			"kind", "bucket", "name", buc.Name, "id", buc.ID, "action", "create", "error", err)
+		return nil, err
+	}
+	c.logger.InfoContext(c.context(), "Bucket created",
+		"kind", "bucket", "name", created.Name, "id", created.Id, "action", "create", "duration", time.Since(start))
+	return created, nil
+}
+
+// CreateBucket creates a bucket using the default client initialised by Utils().
+// See Client.CreateBucket for details.
+func CreateBucket(buc BucketType) (*models.Bucket, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateBucket(buc)
+}
+
+// CreateBucketContext is like CreateBucket but sends its requests with ctx.
+func (c *Client) CreateBucketContext(ctx context.Context, buc BucketType) (*models.Bucket, error) {
+	return c.withContext(ctx).CreateBucket(buc)
+}
+
+// CreateBucketContext is like CreateBucket but honours ctx, using the default client initialised by Utils().
+// See Client.CreateBucketContext for details.
+func CreateBucketContext(ctx context.Context, buc BucketType) (*models.Bucket, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateBucketContext(ctx, buc)
+}
+
+// UpdateBucket brings the existing bucket with the same ID or name in line with the BucketType.
+// Every setting is sent explicitly, since Appwrite resets omitted settings to their defaults.
+// MaxFileSize, Compression, Permissions, AllowedFileExtensions, FileSecurity, Enabled,
+// Encryption and Antivirus keep their current value when left unset in buc.
+//
+// Parameters:
+//   - buc: BucketType struct containing the desired bucket configuration
+//
+// Returns:
+//   - *models.Bucket: Pointer to the updated bucket
+//   - error: Any error that occurred during the operation
+//
+// Example:
+//
+//	enabled := true
+//	bucket := appres.BucketType{
+//		Name:        "user-uploads",
+//		Enabled:     &enabled,
+//		MaxFileSize: 20000000, // 20MB
+//	}
+//	buc, err := client.UpdateBucket(bucket)
+//	if err != nil {
+//		log.Fatal("Failed to update bucket:", err)
+//	}
+func (c *Client) UpdateBucket(buc BucketType) (*models.Bucket, error) {
+	if err := buc.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := c.findBucket(buc.ID, buc.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("bucket %q not found", buc.Name)
+	}
+	if len(DiffBucket(*existing, buc)) == 0 {
+		c.logger.DebugContext(c.context(), "Bucket already up to date",
+			"kind", "bucket", "name", existing.Name, "id", existing.Id, "action", "skip")
+		return existing, nil
+	}
+
+	maxFileSize := existing.MaximumFileSize
+	if buc.MaxFileSize > 0 {
+		maxFileSize = buc.MaxFileSize
+	}
+	compression := existing.Compression
+	if buc.Compression != "" {
+		compression = buc.Compression
+	}
+	perms := existing.Permissions
+	if buc.Permissions != nil {
+		perms = buc.Permissions
+	}
+	extensions := existing.AllowedFileExtensions
+	if buc.AllowedFileExtensions != nil {
+		extensions = buc.AllowedFileExtensions
+	}
+	fileSecurity := existing.FileSecurity
+	if buc.FileSecurity != nil {
+		fileSecurity = *buc.FileSecurity
+	}
+	enabled := existing.Enabled
+	if buc.Enabled != nil {
+		enabled = *buc.Enabled
+	}
+	encryption := existing.Encryption
+	if buc.Encryption != nil {
+		encryption = *buc.Encryption
+	}
+	antivirus := existing.Antivirus
+	if buc.Antivirus != nil {
+		antivirus = *buc.Antivirus
+	}
+	start := time.Now()
+	updated, err := c.storage.UpdateBucket(
+		existing.Id,
+		buc.Name,
+		c.storage.WithUpdateBucketPermissions(perms),
+		c.storage.WithUpdateBucketFileSecurity(fileSecurity),
+		c.storage.WithUpdateBucketEnabled(enabled),
+		c.storage.WithUpdateBucketMaximumFileSize(maxFileSize),
+		c.storage.WithUpdateBucketAllowedFileExtensions(extensions),
+		c.storage.WithUpdateBucketCompression(compression),
+		c.storage.WithUpdateBucketEncryption(encryption),
+		c.storage.WithUpdateBucketAntivirus(antivirus),
+	)
+	if err != nil {
+		c.logger.ErrorContext(c.context(), "Error updating bucket",
+			"kind", "bucket", "name", buc.Name, "id", existing.Id, "action", "update", "error", err)
+		return nil, err
+	}
+	c.logger.InfoContext(c.context(), "Bucket updated",
+		"kind", "bucket", "name", updated.Name, "id", updated.Id, "action", "update", "duration", time.Since(start))
+	return updated, nil
+}
+
+// UpdateBucket updates a bucket using the default client initialised by Utils().
+// See Client.UpdateBucket for details.
+func UpdateBucket(buc BucketType) (*models.Bucket, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateBucket(buc)
+}
+
+// UpdateBucketContext is like UpdateBucket but sends its requests with ctx.
+func (c *Client) UpdateBucketContext(ctx context.Context, buc BucketType) (*models.Bucket, error) {
+	return c.withContext(ctx).UpdateBucket(buc)
+}
+
+// UpdateBucketContext is like UpdateBucket but honours ctx, using the default client initialised by Utils().
+// See Client.UpdateBucketContext for details.
+func UpdateBucketContext(ctx context.Context, buc BucketType) (*models.Bucket, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateBucketContext(ctx, buc)
+}
+
+// EnsureBucket makes sure a bucket with the configured ID or name exists and matches the BucketType.
+// Missing buckets are created with CreateBucket and drifted buckets are updated with UpdateBucket.
+//
+// Parameters:
+//   - buc: BucketType struct containing the desired bucket configuration
+//
+// Returns:
+//   - *models.Bucket: Pointer to the created, updated or existing bucket
+//   - error: Any error that occurred during the operation
+func (c *Client) EnsureBucket(buc BucketType) (*models.Bucket, error) {
+	bucket, err := c.CreateBucket(buc)
+	var drift *BucketDriftError
+	if errors.As(err, &drift) {
+		return c.UpdateBucket(buc)
+	}
+	return bucket, err
+}
+
+// EnsureBucket creates or updates a bucket using the default client initialised by Utils().
+// See Client.EnsureBucket for details.
+func EnsureBucket(buc BucketType) (*models.Bucket, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.EnsureBucket(buc)
+}
+
+// EnsureBucketContext is like EnsureBucket but sends its requests with ctx.
+func (c *Client) EnsureBucketContext(ctx context.Context, buc BucketType) (*models.Bucket, error) {
+	return c.withContext(ctx).EnsureBucket(buc)
+}
+
+// EnsureBucketContext is like EnsureBucket but honours ctx, using the default client initialised by Utils().
+// See Client.EnsureBucketContext for details.
+func EnsureBucketContext(ctx context.Context, buc BucketType) (*models.Bucket, error) {
+	c, err := std()
+	if err != nil {
+		return nil, err
+	}
+	return c.EnsureBucketContext(ctx, buc)
+}
+
+// existingBucket looks the bucket up by ID and then by name. It returns the bucket, or nil if
+// there is none, with a *BucketDriftError if it differs from buc.
+func (c *Client) existingBucket(buc BucketType) (*models.Bucket, error) {
+	existing, err := c.findBucket(buc.ID, buc.Name)
+	if existing == nil || err != nil {
+		return nil, err
+	}
+	if drift := DiffBucket(*existing, buc); len(drift) > 0 {
+		err := &BucketDriftError{Name: buc.Name, ID: existing.Id, Fields: drift}
+		c.logger.DebugContext(c.context(), "Bucket already exists but differs",
+			"kind", "bucket", "name", buc.Name, "id", existing.Id, "action", "skip", "error", err)
+		return existing, err
+	}
+	c.logger.DebugContext(c.context(), "Bucket already exists",
+		"kind", "bucket", "name", existing.Name, "id", existing.Id, "action", "skip")
+	return existing, nil
+}
+
+// findBucket returns the bucket with the given ID, or failing that the given name,
+// or nil if there is none. A bucket matched by name with an ID other than bucketID is
+// reported as an *IDMismatchError.
+func (c *Client) findBucket(bucketID string, name string) (*models.Bucket, error) {
+	buckets, err := c.listBuckets()
+	if err != nil {
+		return nil, err
+	}
+	b := matchBucket(buckets, bucketID, name)
+	if b == nil {
+		return nil, nil
+	}
+	if err := checkID("bucket", name, bucketID, b.Id); err != nil {
+		c.logger.ErrorContext(c.context(), "Bucket exists with another ID",
+			"kind", "bucket", "name", name, "id", b.Id, "action", "skip", "error", err)
+		return nil, err
+	}
+	return b, nil
+}
+
+// matchBucket returns the bucket with the given ID, or failing that the given name.
+func matchBucket(buckets []models.Bucket, bucketID string, name string) *models.Bucket {
+	if bucketID != "" {
+		for _, b := range buckets {
+			if b.Id == bucketID {
+				return &b
+			}
+		}
+	}
+	for _, b := range buckets {
+		if b.Name == name {
+			return &b
+		}
+	}
+	return nil
+}
+
+// BucketDriftError is returned by CreateBucket when a bucket with the requested name already
+// exists but its settings differ from the BucketType.
+type BucketDriftError struct {
+	// Name is the name of the drifted bucket
+	Name string
+
+	// ID is the ID of the existing bucket
+	ID string
+
+	// Fields lists every setting that differs
+	Fields []FieldDrift
+}
+
+func (e *BucketDriftError) Error() string {
+	return fmt.Sprintf("bucket %q differs from definition: %s", e.Name, formatDrift(e.Fields))
+}
+
+// DiffBucket compares an existing bucket with the requested BucketType and returns every
+// setting that differs. An empty result means the bucket matches.
+//
+// MaxFileSize, Compression, FileSecurity, Enabled, Encryption and Antivirus are only compared
+// when set, and Permissions and AllowedFileExtensions only when non-nil; their order is
+// ignored. Permissions are compared with permissions.Equal, so write("any") matches the
+// create, update and delete permissions Appwrite stores for it.
+//
+// Parameters:
+//   - live: The bucket as returned by Appwrite
+//   - buc: The requested bucket configuration
+//
+// Returns:
+//   - []FieldDrift: Every mismatched setting, or nil if the bucket matches
+func DiffBucket(live models.Bucket, buc BucketType) []FieldDrift {
+	var drift []FieldDrift
+	add := func(field string, h, w interface{}) {
+		drift = append(drift, FieldDrift{Field: field, Have: h, Want: w})
+	}
+	if buc.Permissions != nil && !permissions.Equal(live.Permissions, buc.Permissions) {
+		add("Permissions", live.Permissions, buc.Permissions)
+	}
+	if buc.FileSecurity != nil && live.FileSecurity != *buc.FileSecurity {
+		add("FileSecurity", live.FileSecurity, *buc.FileSecurity)
+	}
+	if buc.Enabled != nil && live.Enabled != *buc.Enabled {
+		add("Enabled", live.Enabled, *buc.Enabled)
+	}
+	if buc.MaxFileSize > 0 && live.MaximumFileSize != buc.MaxFileSize {
+		add("MaxFileSize", live.MaximumFileSize, buc.MaxFileSize)
+	}
+	if buc.AllowedFileExtensions != nil && !sameSet(live.AllowedFileExtensions, buc.AllowedFileExtensions) {
+		add("AllowedFileExtensions", live.AllowedFileExtensions, buc.AllowedFileExtensions)
+	}
+	if buc.Compression != "" && live.Compression != buc.Compression {
+		add("Compression", live.Compression, buc.Compression)
+	}
+	if buc.Encryption != nil && live.Encryption != *buc.Encryption {
+		add("Encryption", live.Encryption, *buc.Encryption)
+	}
+	if buc.Antivirus != nil && live.Antivirus != *buc.Antivirus {
+		add("Antivirus", live.Antivirus, *buc.Antivirus)
+	}
+	return drift
+}
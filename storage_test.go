@@ -0,0 +1,372 @@
+package appres
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/appwrite/sdk-for-go/models"
+)
+
+// bucketServer is an Appwrite stand-in serving the bucket endpoints, and a project without
+// databases for Plan. It answers list requests with its buckets and records the decoded body
+// of every create and update request.
+//
+// With conflict set, a create request adds the bucket but is answered with 409 Conflict, as
+// when the response to an earlier attempt was lost. List requests fail with 500 from the
+// failListFrom-th on, if set.
+type bucketServer struct {
+	mu           sync.Mutex
+	buckets      []models.Bucket
+	bodies       []map[string]interface{}
+	requests     int
+	lists        int
+	conflict     bool
+	failListFrom int
+}
+
+func (s *bucketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v1/health/version" {
+		fmt.Fprint(w, `{"version":"1.5.0"}`)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/databases":
+		fmt.Fprint(w, `{"total":0,"databases":[]}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/storage/buckets":
+		s.lists++
+		if s.failListFrom > 0 && s.lists >= s.failListFrom {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"server error","code":500}`)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total": len(s.buckets), "buckets": s.buckets})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/storage/buckets",
+		r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/v1/storage/buckets/"):
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.bodies = append(s.bodies, body)
+		id, _ := body["bucketId"].(string)
+		if r.Method == http.MethodPut {
+			id = strings.TrimPrefix(r.URL.Path, "/v1/storage/buckets/")
+		}
+		name, _ := body["name"].(string)
+		if s.conflict {
+			s.buckets = append(s.buckets, models.Bucket{Id: id, Name: name, Enabled: true})
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message":"bucket already exists","code":409}`)
+			return
+		}
+		json.NewEncoder(w).Encode(models.Bucket{Id: id, Name: name})
+	default:
+		http.Error(w, `{"message":"not found","code":404}`, http.StatusNotFound)
+	}
+}
+
+// newBucketTestClient starts a bucketServer holding the given buckets and returns a client
+// connected to it.
+func newBucketTestClient(t *testing.T, buckets ...models.Bucket) (*Client, *bucketServer) {
+	t.Helper()
+	fake := &bucketServer{buckets: buckets}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	c, err := NewClient(
+		WithEndpoint(srv.URL+"/v1"),
+		WithProject("test"),
+		WithKey("test"),
+		WithRetry(NoRetry),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c, fake
+}
+
+// wantBody checks that the request body carries exactly the wanted values for the given
+// keys, with a nil want meaning the key must be left out.
+func wantBody(t *testing.T, body map[string]interface{}, want map[string]interface{}) {
+	t.Helper()
+	for key, w := range want {
+		got, ok := body[key]
+		switch {
+		case w == nil && ok:
+			t.Errorf("%s: sent %v, want it left out", key, got)
+		case w != nil && !ok:
+			t.Errorf("%s: left out, want %v", key, w)
+		case w != nil && fmt.Sprint(got) != fmt.Sprint(w):
+			t.Errorf("%s: sent %v, want %v", key, got, w)
+		}
+	}
+}
+
+// bucketCases give every boolean setting a different run of values, so a setting sent in
+// the place of another is caught by at least one case.
+var bucketCases = []struct {
+	name string
+	buc  BucketType
+	want map[string]interface{}
+}{
+	{
+		name: "first",
+		buc: BucketType{
+			Name:                  "uploads",
+			ID:                    "uploads",
+			FileSecurity:          Ptr(false),
+			Enabled:               Ptr(false),
+			Encryption:            Ptr(true),
+			Antivirus:             Ptr(true),
+			Compression:           "gzip",
+			AllowedFileExtensions: []string{"jpg", "png"},
+			MaxFileSize:           1000,
+		},
+		want: map[string]interface{}{
+			"fileSecurity":          false,
+			"enabled":               false,
+			"encryption":            true,
+			"antivirus":             true,
+			"compression":           "gzip",
+			"allowedFileExtensions": []interface{}{"jpg", "png"},
+			"maximumFileSize":       1000,
+		},
+	},
+	{
+		name: "second",
+		buc: BucketType{
+			Name:                  "uploads",
+			ID:                    "uploads",
+			FileSecurity:          Ptr(false),
+			Enabled:               Ptr(true),
+			Encryption:            Ptr(false),
+			Antivirus:             Ptr(true),
+			Compression:           "zstd",
+			AllowedFileExtensions: []string{"pdf"},
+			MaxFileSize:           2000,
+		},
+		want: map[string]interface{}{
+			"fileSecurity":          false,
+			"enabled":               true,
+			"encryption":            false,
+			"antivirus":             true,
+			"compression":           "zstd",
+			"allowedFileExtensions": []interface{}{"pdf"},
+			"maximumFileSize":       2000,
+		},
+	},
+	{
+		name: "third",
+		buc: BucketType{
+			Name:         "uploads",
+			ID:           "uploads",
+			FileSecurity: Ptr(true),
+			Enabled:      Ptr(false),
+			Encryption:   Ptr(false),
+			Antivirus:    Ptr(false),
+			Compression:  "none",
+			MaxFileSize:  3000,
+		},
+		want: map[string]interface{}{
+			"fileSecurity":    true,
+			"enabled":         false,
+			"encryption":      false,
+			"antivirus":       false,
+			"compression":     "none",
+			"maximumFileSize": 3000,
+		},
+	},
+}
+
+func TestCreateBucketSendsEachSetting(t *testing.T) {
+	for _, tc := range bucketCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, fake := newBucketTestClient(t)
+			if _, err := c.CreateBucket(tc.buc); err != nil {
+				t.Fatalf("CreateBucket: %v", err)
+			}
+			if len(fake.bodies) != 1 {
+				t.Fatalf("got %d create requests, want 1", len(fake.bodies))
+			}
+			wantBody(t, fake.bodies[0], tc.want)
+		})
+	}
+}
+
+func TestCreateBucketLeavesUnsetSettingsOut(t *testing.T) {
+	c, fake := newBucketTestClient(t)
+	if _, err := c.CreateBucket(BucketType{Name: "uploads"}); err != nil {
+		t.Fatalf("CreateBucket: %v", err)
+	}
+	if len(fake.bodies) != 1 {
+		t.Fatalf("got %d create requests, want 1", len(fake.bodies))
+	}
+	wantBody(t, fake.bodies[0], map[string]interface{}{
+		"maximumFileSize":       nil,
+		"fileSecurity":          nil,
+		"enabled":               nil,
+		"encryption":            nil,
+		"antivirus":             nil,
+		"compression":           nil,
+		"allowedFileExtensions": nil,
+		"permissions":           nil,
+	})
+}
+
+func TestUpdateBucketSendsEachSetting(t *testing.T) {
+	live := models.Bucket{
+		Id:                    "uploads",
+		Name:                  "uploads",
+		FileSecurity:          true,
+		Enabled:               true,
+		Encryption:            true,
+		Antivirus:             false,
+		Compression:           "none",
+		AllowedFileExtensions: []string{"gif"},
+		MaximumFileSize:       500,
+	}
+	for _, tc := range bucketCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, fake := newBucketTestClient(t, live)
+			if _, err := c.UpdateBucket(tc.buc); err != nil {
+				t.Fatalf("UpdateBucket: %v", err)
+			}
+			if len(fake.bodies) != 1 {
+				t.Fatalf("got %d update requests, want 1", len(fake.bodies))
+			}
+			wantBody(t, fake.bodies[0], tc.want)
+		})
+	}
+}
+
+func TestUpdateBucketKeepsUnsetSettings(t *testing.T) {
+	live := models.Bucket{
+		Id:                    "uploads",
+		Name:                  "uploads",
+		FileSecurity:          true,
+		Enabled:               false,
+		Encryption:            true,
+		Antivirus:             false,
+		Compression:           "zstd",
+		AllowedFileExtensions: []string{"gif"},
+		MaximumFileSize:       500,
+	}
+	c, fake := newBucketTestClient(t, live)
+	if _, err := c.UpdateBucket(BucketType{Name: "uploads", Compression: "gzip"}); err != nil {
+		t.Fatalf("UpdateBucket: %v", err)
+	}
+	if len(fake.bodies) != 1 {
+		t.Fatalf("got %d update requests, want 1", len(fake.bodies))
+	}
+	wantBody(t, fake.bodies[0], map[string]interface{}{
+		"fileSecurity":          true,
+		"enabled":               false,
+		"encryption":            true,
+		"antivirus":             false,
+		"compression":           "gzip",
+		"allowedFileExtensions": []interface{}{"gif"},
+		"maximumFileSize":       500,
+	})
+}
+
+func TestBucketValidationFailsBeforeAnyRequest(t *testing.T) {
+	extensions := make([]string, maxBucketFileExtensions+1)
+	for i := range extensions {
+		extensions[i] = fmt.Sprintf("x%d", i)
+	}
+	tests := []struct {
+		name string
+		buc  BucketType
+	}{
+		{"invalid compression", BucketType{Name: "uploads", Compression: "brotli"}},
+		{"too many extensions", BucketType{Name: "uploads", AllowedFileExtensions: extensions}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, fake := newBucketTestClient(t, models.Bucket{Id: "uploads", Name: "uploads"})
+			if _, err := c.CreateBucket(tc.buc); err == nil {
+				t.Error("CreateBucket: got no error")
+			}
+			if _, err := c.UpdateBucket(tc.buc); err == nil {
+				t.Error("UpdateBucket: got no error")
+			}
+			if _, err := c.EnsureBucket(tc.buc); err == nil {
+				t.Error("EnsureBucket: got no error")
+			}
+			if fake.requests != 0 {
+				t.Errorf("got %d requests, want none", fake.requests)
+			}
+		})
+	}
+}
+
+func TestCreateBucketRecoversFromConflict(t *testing.T) {
+	c, fake := newBucketTestClient(t)
+	fake.conflict = true
+	buc, err := c.CreateBucket(BucketType{Name: "uploads", ID: "uploads"})
+	if err != nil {
+		t.Fatalf("CreateBucket: %v", err)
+	}
+	if buc.Id != "uploads" {
+		t.Errorf("got bucket %q, want uploads", buc.Id)
+	}
+}
+
+func TestCreateBucketReportsConflictLookupError(t *testing.T) {
+	c, fake := newBucketTestClient(t)
+	fake.conflict = true
+	fake.failListFrom = 2
+	_, err := c.CreateBucket(BucketType{Name: "uploads", ID: "uploads"})
+	if !isConflict(err) {
+		t.Errorf("got error %v, want the 409 Conflict", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "server error") {
+		t.Errorf("got error %v, want it to include the lookup error", err)
+	}
+}
+
+func TestBucketIDMismatch(t *testing.T) {
+	live := models.Bucket{Id: "65a1f0c2", Name: "uploads", Enabled: true}
+	buc := BucketType{Name: "uploads", ID: "uploads"}
+	c, fake := newBucketTestClient(t, live)
+	for name, call := range map[string]func(BucketType) (*models.Bucket, error){
+		"CreateBucket": c.CreateBucket,
+		"UpdateBucket": c.UpdateBucket,
+		"EnsureBucket": c.EnsureBucket,
+	} {
+		_, err := call(buc)
+		var mismatch *IDMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Errorf("%s: got error %v, want *IDMismatchError", name, err)
+			continue
+		}
+		if mismatch.ID != "uploads" || mismatch.ExistingID != "65a1f0c2" {
+			t.Errorf("%s: got IDs %q and %q, want uploads and 65a1f0c2", name, mismatch.ID, mismatch.ExistingID)
+		}
+	}
+	if len(fake.bodies) != 0 {
+		t.Errorf("got %d create or update requests, want none", len(fake.bodies))
+	}
+
+	changes, err := c.Plan(&Schema{Buckets: []BucketType{buc}})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(changes.Changes) != 1 || changes.Changes[0].Action != ActionConflict {
+		t.Errorf("got plan %v, want a single conflict", changes.Changes)
+	}
+
+	if _, err := c.CreateBucket(BucketType{Name: "uploads"}); err != nil {
+		t.Errorf("CreateBucket without ID: %v", err)
+	}
+}
@@ -6,12 +6,15 @@ package appres
 //
 // Supported attribute types:
 //   - "string": Text attributes with size, encryption, and array support
-//   - "email": Email validation attributes with array support  
+//   - "email": Email validation attributes with array support
 //   - "integer": Integer attributes with min/max constraints and array support
+//   - "float": Floating point attributes with min/max constraints and array support
 //   - "datetime": Date and time attributes with array support
 //   - "boolean": Boolean (true/false) attributes with array support
 //   - "relationship": Relationship attributes linking collections
 //   - "url": URL validation attributes with array support
+//   - "enum": String attributes limited to a list of elements, with array support
+//   - "ip": IPv4 and IPv6 address validation attributes with array support
 //
 // Example usage:
 //
@@ -29,111 +32,237 @@ package appres
 //	intAttr := AttributeType{
 //		Type:     "integer",
 //		Name:     "age",
-//		Required: true,
+//		Required: false,
 //		Min:      0,
 //		Max:      120,
-//		Default:  "18",
+//		Default:  18,
 //		Array:    false,
 //	}
+//
+//	// Enum attribute example:
+//	enumAttr := AttributeType{
+//		Type:     "enum",
+//		Name:     "status",
+//		Elements: []string{"draft", "published", "archived"},
+//		Default:  "draft",
+//	}
 type AttributeType struct {
-	// Type specifies the attribute type. Supported values: "string", "email", "integer", "datetime", "boolean"
-	Type string
-	
-	// Name is the key/identifier for the attribute in the collection
-	Name string
-	
+	// Type specifies the attribute type. Supported values: "string", "email", "integer", "float",
+	// "datetime", "boolean", "relationship", "url", "enum", "ip"
+	Type string `json:"type" yaml:"type"`
+
+	// Name is the key/identifier for the attribute in the collection.
+	// For relationship attributes it is optional and defaults to the related collection ID
+	Name string `json:"name" yaml:"name"`
+
 	// Size defines the maximum length for string and email attributes
-	Size int
-	
+	Size int `json:"size,omitempty" yaml:"size,omitempty"`
+
 	// Required determines whether this attribute must have a value
-	Required bool
-	
-	// Unique bool - not implemented in sdk (https://github.com/appwrite/sdk-for-go/blob/main/databases/databases.go)
-	
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// Unique constraints are not an attribute setting in Appwrite; define an IndexType
+	// of type "unique" on the collection instead
+
 	// Default is the default value assigned to the attribute if no value is provided
-	Default interface{}
-	
+	Default interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+
 	// Array indicates whether the attribute can store multiple values as an array
-	Array bool
-	
+	Array bool `json:"array,omitempty" yaml:"array,omitempty"`
+
 	// Encrypt determines whether the attribute value should be encrypted at rest
 	// Note: Only available for string attributes
-	Encrypt bool
-	
-	// Min is the minimum value for integer attributes (optional)
+	Encrypt bool `json:"encrypt,omitempty" yaml:"encrypt,omitempty"`
+
+	// Min is the minimum value for integer and float attributes (optional)
 	// If not set (0), no minimum constraint will be applied
-	Min interface{}
-	
-	// Max is the maximum value for integer attributes (optional)
+	Min interface{} `json:"min,omitempty" yaml:"min,omitempty"`
+
+	// Max is the maximum value for integer and float attributes (optional)
 	// If not set (0), no maximum constraint will be applied
-	Max interface{}
+	Max interface{} `json:"max,omitempty" yaml:"max,omitempty"`
+
+	// Elements lists the allowed values of an enum attribute
+	Elements []string `json:"elements,omitempty" yaml:"elements,omitempty"`
 
 	// The ID of the collection this relationship attribute links to.
-	RelatedCollectionID string
+	RelatedCollectionID string `json:"relatedCollectionId,omitempty" yaml:"relatedCollectionId,omitempty"`
+
+	// The name of the collection this relationship attribute links to, used instead of
+	// RelatedCollectionID when the ID is not known in advance. The collection is looked up in
+	// the same database when the attribute is created.
+	RelatedCollection string `json:"relatedCollection,omitempty" yaml:"relatedCollection,omitempty"`
 
 	// The type of relationship
 	// must be one of; `oneToOne`, `oneToMany`, `manyToOne`, `manyToMany`.
 	// Reference documentation: https://appwrite.io/docs/products/databases/relationships#types
-	RelationshipType string
+	RelationshipType string `json:"relationshipType,omitempty" yaml:"relationshipType,omitempty"`
 
 	// Enable two-way directionality
 	// false: One-way - The relationship is only visible to one side of the relation. This is similar to a tree data structure.
 	// true:  Two-way - The relationship is visible to both sides of the relationship. This is similar to a graph data structure.
 	// Reference documentation: https://appwrite.io/docs/products/databases/relationships#directionality
-	TwoWay bool
+	TwoWay bool `json:"twoWay,omitempty" yaml:"twoWay,omitempty"`
 
 	// The key/identifier used to name the two-way relationship on the related collection side.
-	TwoWayKey string
+	TwoWayKey string `json:"twoWayKey,omitempty" yaml:"twoWayKey,omitempty"`
 
 	// On delete constraint behaviour for relationship attributes
-	// must be one of; `restrict`, `cascade`, `setnull`.
+	// must be one of; `restrict`, `cascade`, `setNull`.
 	// Restrict: If a row has at least one related row, it cannot be deleted.
 	// Cascade:	If a row has related rows, when it is deleted, the related rows are also deleted.
 	// Set null: If a row has related rows, when it is deleted, the related rows are kept with their relationship column set to null.
 	// Reference documentation: https://appwrite.io/docs/products/databases/relationships#on-delete
-	OnDelete string
+	OnDelete string `json:"onDelete,omitempty" yaml:"onDelete,omitempty"`
 }
 
 // BucketType defines the configuration for creating storage buckets in Appwrite.
 // It contains all the necessary fields to specify bucket behavior, security, and constraints.
 //
+// FileSecurity, Enabled, Encryption and Antivirus are only applied when set, so a BucketType
+// that leaves them out keeps whatever the bucket already has. Appwrite creates buckets with
+// file security off and the other three on.
+//
 // Example usage:
 //
 //	bucket := BucketType{
 //		Name:         "user-uploads",
-//		Enabled:      true,
-//		FileSecurity: true,
+//		FileSecurity: Ptr(true),
 //		MaxFileSize:  10000000, // 10MB
 //		Permissions:  []string{"read(\"any\")"},
 //		Compression:  "gzip",
-//		Encryption:   true,
-//		Antivirus:    true,
+//		Antivirus:    Ptr(false),
 //	}
 type BucketType struct {
 	// Name is the bucket identifier
-	Name string
+	Name string `json:"name" yaml:"name"`
+
+	// ID is an optional fixed bucket ID, so the bucket has the same ID in every environment.
+	// A unique ID is generated when it is empty
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
 
 	// Permissions is an array of permission strings (e.g. "read(\"any\")")
-	Permissions []string
+	Permissions []string `json:"permissions,omitempty" yaml:"permissions,omitempty"`
 
-	// FileSecurity enables file-level security permissions
-	FileSecurity bool
+	// FileSecurity enables file-level security permissions. Leave nil to keep the existing
+	// setting; new buckets have it disabled
+	FileSecurity *bool `json:"fileSecurity,omitempty" yaml:"fileSecurity,omitempty"`
 
-	// Enabled determines if the bucket is accessible to users
-	Enabled bool
+	// Enabled determines if the bucket is accessible to users. Leave nil to keep the
+	// existing setting; new buckets are enabled by default
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 
 	// MaxFileSize is the maximum file size allowed in bytes (max: 30MB)
-	MaxFileSize int
+	MaxFileSize int `json:"maxFileSize,omitempty" yaml:"maxFileSize,omitempty"`
 
 	// AllowedFileExtensions limits file types (max: 100 extensions)
-	AllowedFileExtensions []string
+	AllowedFileExtensions []string `json:"allowedFileExtensions,omitempty" yaml:"allowedFileExtensions,omitempty"`
 
 	// Compression algorithm: "none", "gzip", or "zstd"
-	Compression string
+	Compression string `json:"compression,omitempty" yaml:"compression,omitempty"`
+
+	// Encryption enables file encryption at rest. Leave nil to keep the existing setting;
+	// new buckets are encrypted by default
+	Encryption *bool `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+
+	// Antivirus enables virus scanning for uploaded files. Leave nil to keep the existing
+	// setting; new buckets are scanned by default
+	Antivirus *bool `json:"antivirus,omitempty" yaml:"antivirus,omitempty"`
+}
+
+// DatabaseType describes a database and the collections it contains.
+// It is the database entry of a Schema document.
+//
+// Example usage:
+//
+//	db := DatabaseType{
+//		Name: "my-app-database",
+//		Collections: []CollectionType{
+//			{Name: "users"},
+//		},
+//	}
+type DatabaseType struct {
+	// Name is the database name, used to find an existing database before creating a new one
+	Name string `json:"name" yaml:"name"`
+
+	// ID is an optional fixed database ID, so the database has the same ID in every environment.
+	// A unique ID is generated when it is empty
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Collections lists the collections to create within the database
+	Collections []CollectionType `json:"collections,omitempty" yaml:"collections,omitempty"`
+}
+
+// CollectionType describes a collection, its settings and the attributes it contains.
+// It is the collection entry of a DatabaseType.
+//
+// Permissions, DocumentSecurity and Enabled are only applied when set, so a CollectionType
+// that leaves them out keeps whatever the collection already has.
+//
+// Example usage:
+//
+//	col := CollectionType{
+//		Name:             "users",
+//		ID:               "users",
+//		Permissions:      []string{"read(\"users\")", "create(\"users\")"},
+//		DocumentSecurity: Ptr(true),
+//		Enabled:          Ptr(true),
+//		Attributes: []AttributeType{
+//			{Type: "string", Name: "username", Size: 50, Required: true},
+//		},
+//	}
+type CollectionType struct {
+	// Name is the collection name, used to find an existing collection before creating a new one
+	Name string `json:"name" yaml:"name"`
+
+	// ID is an optional fixed collection ID, so application code can refer to the collection
+	// by the same ID in every environment. A unique ID is generated when it is empty
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Permissions is an array of permission strings (e.g. "read(\"any\")").
+	// Leave nil to keep the existing permissions
+	Permissions []string `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+
+	// DocumentSecurity enables document-level permissions in addition to the collection
+	// permissions. Leave nil to keep the existing setting; new collections have it disabled
+	DocumentSecurity *bool `json:"documentSecurity,omitempty" yaml:"documentSecurity,omitempty"`
+
+	// Enabled determines if the collection is accessible to users. Leave nil to keep the
+	// existing setting; new collections are enabled by default
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+
+	// Attributes lists the attributes to create within the collection, in order
+	Attributes []AttributeType `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+
+	// Indexes lists the indexes to create once the attributes are available
+	Indexes []IndexType `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+}
+
+// IndexType defines the configuration for creating indexes in Appwrite collections.
+//
+// Supported index types:
+//   - "key": A plain index to speed up queries and sorting
+//   - "unique": A key index that also rejects duplicate values
+//   - "fulltext": A full-text search index on string attributes
+//
+// Example usage:
+//
+//	idx := IndexType{
+//		Key:        "username_unique",
+//		Type:       "unique",
+//		Attributes: []string{"username"},
+//		Orders:     []string{"ASC"},
+//	}
+type IndexType struct {
+	// Key is the identifier of the index in the collection
+	Key string `json:"key" yaml:"key"`
+
+	// Type specifies the index type. Supported values: "key", "unique", "fulltext"
+	Type string `json:"type" yaml:"type"`
 
-	// Encryption enables file encryption at rest
-	Encryption bool
+	// Attributes lists the attribute keys covered by the index
+	Attributes []string `json:"attributes" yaml:"attributes"`
 
-	// Antivirus enables virus scanning for uploaded files  
-	Antivirus bool
+	// Orders sets the sort order ("ASC" or "DESC") for each attribute (optional)
+	Orders []string `json:"orders,omitempty" yaml:"orders,omitempty"`
 }
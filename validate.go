@@ -0,0 +1,335 @@
+package appres
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/Haepapa/appres/permissions"
+)
+
+// keyPattern matches the keys and IDs Appwrite accepts: up to 36 letters, digits, periods,
+// hyphens and underscores, not starting with a special character.
+var keyPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,35}$`)
+
+// Allowed values of the relationship settings.
+var (
+	relationshipTypes = []string{"oneToOne", "oneToMany", "manyToOne", "manyToMany"}
+	onDeleteActions   = []string{"restrict", "cascade", "setNull"}
+)
+
+// Validate checks the attribute definition without contacting Appwrite and returns every
+// problem found, joined with errors.Join, or nil if the definition is valid.
+//
+// It checks that the key is valid, including the key a relationship without a name takes from
+// its related collection, that the type is supported, that the default, Min and
+// Max have the right type and lie within the bounds, that required and array attributes have
+// no default, that string attributes have a size, that only string attributes are encrypted,
+// that enum attributes list their elements, and that relationship attributes name the related
+// collection and use a valid relationship type and on-delete action.
+//
+// CreateAttribute and UpdateAttribute call Validate before making any request.
+//
+// Example:
+//
+//	if err := attr.Validate(); err != nil {
+//		log.Fatal(err) // one line per problem
+//	}
+func (a AttributeType) Validate() error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("attribute %q: "+format, append([]interface{}{a.key()}, args...)...))
+	}
+
+	// A relationship without a name is keyed by its related collection, which must then be a valid key too
+	switch key := a.key(); {
+	case key == "" && a.Type != "relationship":
+		add("name is required")
+	case key != "" && !keyPattern.MatchString(key) && a.Name == "":
+		add("key taken from the related collection must be at most 36 letters, digits, periods, hyphens or underscores and not start with a special character; set Name")
+	case key != "" && !keyPattern.MatchString(key):
+		add("key must be at most 36 letters, digits, periods, hyphens or underscores and not start with a special character")
+	}
+	if a.Encrypt && a.Type != "string" {
+		add("encryption is only supported for string attributes")
+	}
+	if a.Default != nil && a.Default != "" {
+		if a.Required {
+			add("required attributes cannot have a default value")
+		}
+		if a.Array {
+			add("array attributes cannot have a default value")
+		}
+	}
+
+	switch a.Type {
+	case "string":
+		if a.Size <= 0 {
+			add("size must be greater than 0")
+		}
+		if a.Default != nil {
+			if _, ok := a.Default.(string); !ok {
+				add("default value for string attribute must be a string")
+			}
+		}
+	case "email", "url":
+		if a.Default != nil {
+			if _, ok := a.Default.(string); !ok {
+				add("default value for %s attribute must be a string", a.Type)
+			}
+		}
+	case "ip":
+		if a.Default != nil {
+			s, ok := a.Default.(string)
+			if !ok {
+				add("default value for ip attribute must be a string")
+			} else if net.ParseIP(s) == nil {
+				add("default value for ip attribute must be a valid IPv4 or IPv6 address, got %q", s)
+			}
+		}
+	case "enum":
+		if len(a.Elements) == 0 {
+			add("enum attribute must list at least one element")
+		}
+		if a.Default != nil {
+			s, ok := a.Default.(string)
+			if !ok {
+				add("default value for enum attribute must be a string")
+			} else if !slices.Contains(a.Elements, s) {
+				add("default value for enum attribute must be one of its elements, got %q", s)
+			}
+		}
+	case "integer":
+		for _, field := range []struct {
+			name  string
+			value interface{}
+		}{{"default", a.Default}, {"min", a.Min}, {"max", a.Max}} {
+			if field.value == nil {
+				continue
+			}
+			if _, ok := field.value.(int); !ok {
+				add("%s value for integer attribute must be an int", field.name)
+			}
+		}
+		errs = append(errs, a.validateRange()...)
+	case "float":
+		for _, field := range []struct {
+			name  string
+			value interface{}
+		}{{"default", a.Default}, {"min", a.Min}, {"max", a.Max}} {
+			if field.value == nil {
+				continue
+			}
+			if _, ok := toFloat(field.value); !ok {
+				add("%s value for float attribute must be a number", field.name)
+			}
+		}
+		errs = append(errs, a.validateRange()...)
+	case "datetime":
+		if a.Default != nil {
+			s, ok := a.Default.(string)
+			if !ok {
+				add("default value for datetime attribute must be a string")
+			} else if _, err := time.Parse(time.RFC3339, s); err != nil {
+				add("default value for datetime attribute must be a valid RFC3339 datetime string: %v", err)
+			}
+		}
+	case "boolean":
+		if a.Default != nil {
+			if _, ok := a.Default.(bool); !ok {
+				add("default value for boolean attribute must be a bool")
+			}
+		}
+	case "relationship":
+		if a.RelatedCollectionID == "" && a.RelatedCollection == "" {
+			add("relationship attribute must set RelatedCollectionID or RelatedCollection")
+		}
+		if !slices.Contains(relationshipTypes, a.RelationshipType) {
+			add("relationship type must be one of oneToOne, oneToMany, manyToOne or manyToMany, got %q", a.RelationshipType)
+		}
+		if a.OnDelete != "" && !slices.Contains(onDeleteActions, a.OnDelete) {
+			add("on delete must be one of restrict, cascade or setNull, got %q", a.OnDelete)
+		}
+		if a.TwoWayKey != "" && !keyPattern.MatchString(a.TwoWayKey) {
+			add("two-way key %q is not a valid key", a.TwoWayKey)
+		}
+	default:
+		add("unsupported attribute type: %s", a.Type)
+	}
+	return errors.Join(errs...)
+}
+
+// validateRange checks that Min is not above Max and that the default lies between them.
+// Values of the wrong type are skipped, since they are reported separately.
+func (a AttributeType) validateRange() []error {
+	var errs []error
+	min, hasMin := toFloat(a.Min)
+	max, hasMax := toFloat(a.Max)
+	if hasMin && hasMax && min > max {
+		errs = append(errs, fmt.Errorf("attribute %q: min %v is greater than max %v", a.key(), a.Min, a.Max))
+	}
+	if def, ok := toFloat(a.Default); ok {
+		if (hasMin && def < min) || (hasMax && def > max) {
+			errs = append(errs, fmt.Errorf("attribute %q: default value %v is outside the range of min and max", a.key(), a.Default))
+		}
+	}
+	return errs
+}
+
+// Limits enforced by Appwrite on bucket settings.
+const (
+	maxBucketFileSize       = 30000000
+	maxBucketFileExtensions = 100
+)
+
+// bucketCompressions lists the compression algorithms Appwrite supports.
+var bucketCompressions = []string{"none", "gzip", "zstd"}
+
+// Validate checks the bucket definition without contacting Appwrite and returns every
+// problem found, joined with errors.Join, or nil if the definition is valid.
+//
+// It checks that the bucket has a name and a valid ID when one is set, that MaxFileSize is at
+// most 30MB, that at most 100 file extensions are allowed, that Compression is "none", "gzip"
+// or "zstd" when set, and that every permission string can be parsed.
+//
+// CreateBucket and UpdateBucket call Validate before making any request.
+func (b BucketType) Validate() error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("bucket %q: "+format, append([]interface{}{b.Name}, args...)...))
+	}
+	if b.Name == "" {
+		add("name is required")
+	}
+	if b.ID != "" && !keyPattern.MatchString(b.ID) {
+		add("ID %q must be at most 36 letters, digits, periods, hyphens or underscores and not start with a special character", b.ID)
+	}
+	if b.MaxFileSize < 0 || b.MaxFileSize > maxBucketFileSize {
+		add("MaxFileSize must be between 0 and 30MB")
+	}
+	if len(b.AllowedFileExtensions) > maxBucketFileExtensions {
+		add("AllowedFileExtensions must not list more than %d extensions, got %d",
+			maxBucketFileExtensions, len(b.AllowedFileExtensions))
+	}
+	if b.Compression != "" && !slices.Contains(bucketCompressions, b.Compression) {
+		add("Compression must be one of none, gzip or zstd, got %q", b.Compression)
+	}
+	for _, p := range b.Permissions {
+		if _, err := permissions.Parse(p); err != nil {
+			add("%v", err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks every attribute and bucket in the schema and returns all problems found,
+// joined with errors.Join, or nil if the schema is valid. Apply and Plan call it before
+// making any request.
+func (s *Schema) Validate() error {
+	var errs []error
+	for _, db := range s.Databases {
+		for _, col := range db.Collections {
+			for _, att := range col.Attributes {
+				for _, err := range unjoin(att.Validate()) {
+					errs = append(errs, fmt.Errorf("database %q: collection %q: %w", db.Name, col.Name, err))
+				}
+			}
+		}
+	}
+	for _, db := range s.Databases {
+		errs = append(errs, checkRelationships(db)...)
+	}
+	for _, buc := range s.Buckets {
+		errs = append(errs, buc.Validate())
+	}
+	return errors.Join(errs...)
+}
+
+// checkRelationships finds two-way relationships within a database whose reverse side
+// clashes with another attribute of the schema. Appwrite creates the reverse side of a
+// two-way relationship itself, so declaring it as well, for example as a relationship back
+// to the first collection, would fail halfway through Apply.
+func checkRelationships(db DatabaseType) []error {
+	var errs []error
+	reported := map[string]bool{}
+	for i, col := range db.Collections {
+		for _, att := range col.Attributes {
+			if att.Type != "relationship" || !att.TwoWay {
+				continue
+			}
+			j := schemaCollection(db, att.RelatedCollectionID, att.RelatedCollection)
+			if j < 0 {
+				continue
+			}
+			reverseKey := att.TwoWayKey
+			if reverseKey == "" {
+				reverseKey = col.ID
+			}
+			if reverseKey == "" {
+				continue
+			}
+			related := db.Collections[j]
+			if i == j && reverseKey == schemaKey(db, att) {
+				errs = append(errs, fmt.Errorf("database %q: collection %q: relationship %q links the collection to itself, so its two-way key must differ from its key",
+					db.Name, col.Name, att.key()))
+				continue
+			}
+			for _, other := range related.Attributes {
+				if schemaKey(db, other) != reverseKey || reported[fmt.Sprintf("%d/%s", i, att.key())] {
+					continue
+				}
+				if other.Type == "relationship" && schemaCollection(db, other.RelatedCollectionID, other.RelatedCollection) == i {
+					// Report a relationship declared from both sides once, not once per side
+					reported[fmt.Sprintf("%d/%s", j, other.key())] = true
+					errs = append(errs, fmt.Errorf("database %q: collection %q: relationship %q is two-way, so its reverse side %q on collection %q is created automatically and must not be declared as well",
+						db.Name, col.Name, att.key(), reverseKey, related.Name))
+				} else {
+					errs = append(errs, fmt.Errorf("database %q: collection %q: two-way key %q of relationship %q is already used by an attribute of collection %q",
+						db.Name, col.Name, reverseKey, att.key(), related.Name))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// schemaCollection returns the index of the collection in the database definition with the
+// given ID, or failing that the given name, or -1 if there is none.
+func schemaCollection(db DatabaseType, colID string, name string) int {
+	for i, col := range db.Collections {
+		if colID != "" && col.ID == colID {
+			return i
+		}
+	}
+	for i, col := range db.Collections {
+		if name != "" && col.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// schemaKey returns the key an attribute of the database definition will be stored under,
+// using the ID of the related collection from the schema for relationships without a Name.
+func schemaKey(db DatabaseType, att AttributeType) string {
+	if att.Type == "relationship" && att.Name == "" {
+		if j := schemaCollection(db, att.RelatedCollectionID, att.RelatedCollection); j >= 0 && db.Collections[j].ID != "" {
+			return db.Collections[j].ID
+		}
+	}
+	return att.key()
+}
+
+// unjoin returns the errors joined in err, so each can be wrapped on its own.
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err == nil {
+		return nil
+	}
+	return []error{err}
+}
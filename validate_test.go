@@ -0,0 +1,49 @@
+package appres
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttributeValidateKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		att     AttributeType
+		wantErr string
+	}{
+		{"valid name", AttributeType{Type: "string", Name: "title", Size: 10}, ""},
+		{"missing name", AttributeType{Type: "string", Size: 10}, "name is required"},
+		{"invalid name", AttributeType{Type: "string", Name: "my title", Size: 10}, "key must be"},
+		{
+			"relationship keyed by related collection ID",
+			AttributeType{Type: "relationship", RelatedCollectionID: "authors", RelationshipType: "manyToOne"},
+			"",
+		},
+		{
+			"relationship keyed by related collection name",
+			AttributeType{Type: "relationship", RelatedCollection: "Some Name", RelationshipType: "manyToOne"},
+			"key taken from the related collection",
+		},
+		{
+			"relationship with a name and a related collection name",
+			AttributeType{Type: "relationship", Name: "author", RelatedCollection: "Some Name", RelationshipType: "manyToOne"},
+			"",
+		},
+		{
+			"relationship with an invalid name",
+			AttributeType{Type: "relationship", Name: "the author", RelatedCollectionID: "authors", RelationshipType: "manyToOne"},
+			"key must be",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.att.Validate()
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("got error %v, want none", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
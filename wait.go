@@ -0,0 +1,149 @@
+package appres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultWaitTimeout is the timeout used by WaitForAttributes when none is given,
+// and by EnsureIndex while waiting for the attributes an index refers to.
+const DefaultWaitTimeout = 2 * time.Minute
+
+// pollInterval is the delay between status checks while waiting on Appwrite.
+const pollInterval = time.Second
+
+// AttributeStatusError is returned by WaitForAttributes when an attribute is missing or
+// Appwrite reports that it could not be created.
+type AttributeStatusError struct {
+	// Key is the key of the attribute
+	Key string
+
+	// Status is the status reported by Appwrite: "failed" or "stuck", or empty if the
+	// attribute does not exist
+	Status string
+
+	// Message is the error message Appwrite reported for the attribute, if any
+	Message string
+}
+
+func (e *AttributeStatusError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("attribute %q not found", e.Key)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("attribute %q is %s: %s", e.Key, e.Status, e.Message)
+	}
+	return fmt.Sprintf("attribute %q is %s", e.Key, e.Status)
+}
+
+// WaitTimeoutError is returned by WaitForAttributes when attributes are still being
+// processed once the timeout has passed.
+type WaitTimeoutError struct {
+	// Pending lists the keys of the attributes that were not yet available
+	Pending []string
+
+	// Timeout is the timeout that was exceeded
+	Timeout time.Duration
+}
+
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for attributes to become available: %s", e.Timeout, strings.Join(e.Pending, ", "))
+}
+
+// WaitForAttributes blocks until every given attribute in the collection reports status "available".
+// Appwrite creates attributes asynchronously, so an attribute returned by CreateAttribute may
+// not be usable in an index or relationship straight away. WaitForAttributes polls
+// ListAttributes instead of relying on fixed sleeps.
+//
+// Parameters:
+//   - dbID: The ID of the database containing the collection
+//   - colID: The ID of the collection containing the attributes
+//   - keys: The keys of the attributes to wait for
+//   - timeout: How long to wait before giving up; zero or less uses DefaultWaitTimeout
+//
+// Returns:
+//   - error: *AttributeStatusError if an attribute is missing, "failed" or "stuck",
+//     *WaitTimeoutError if the timeout passes first, any error from listing attributes,
+//     or nil once all attributes are available
+//
+// Example:
+//
+//	err := client.WaitForAttributes(db.Id, col.Id, []string{"title", "author"}, 30*time.Second)
+//	if err != nil {
+//		log.Fatal("Attributes not ready:", err)
+//	}
+func (c *Client) WaitForAttributes(dbID string, colID string, keys []string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	start := time.Now()
+	deadline := start.Add(timeout)
+	for {
+		attributes, err := c.listAttributes(dbID, colID)
+		if err != nil {
+			return err
+		}
+		var pending []string
+		for _, key := range keys {
+			var live map[string]interface{}
+			for _, attr := range attributes {
+				if attrName, ok := attr["key"].(string); ok && attrName == key {
+					live = attr
+					break
+				}
+			}
+			if live == nil {
+				return &AttributeStatusError{Key: key}
+			}
+			status, _ := live["status"].(string)
+			switch status {
+			case "available":
+			case "failed", "stuck":
+				message, _ := live["error"].(string)
+				return &AttributeStatusError{Key: key, Status: status, Message: message}
+			default:
+				pending = append(pending, key)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return &WaitTimeoutError{Pending: pending, Timeout: timeout}
+		}
+		c.logger.InfoContext(c.context(), "Waiting for attributes to become available",
+			"kind", "attribute", "name", strings.Join(pending, ", "), "collection", colID, "action", "wait",
+			"duration", time.Since(start))
+		if err := sleep(c.context(), pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// WaitForAttributes waits for attributes using the default client initialised by Utils().
+// See Client.WaitForAttributes for details.
+func WaitForAttributes(dbID string, colID string, keys []string, timeout time.Duration) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.WaitForAttributes(dbID, colID, keys, timeout)
+}
+
+// WaitForAttributesContext is like WaitForAttributes but sends its requests with ctx and
+// returns the context's error as soon as ctx is done, even if the timeout has not passed yet.
+func (c *Client) WaitForAttributesContext(ctx context.Context, dbID string, colID string, keys []string, timeout time.Duration) error {
+	return c.withContext(ctx).WaitForAttributes(dbID, colID, keys, timeout)
+}
+
+// WaitForAttributesContext is like WaitForAttributes but honours ctx, using the default client initialised by Utils().
+// See Client.WaitForAttributesContext for details.
+func WaitForAttributesContext(ctx context.Context, dbID string, colID string, keys []string, timeout time.Duration) error {
+	c, err := std()
+	if err != nil {
+		return err
+	}
+	return c.WaitForAttributesContext(ctx, dbID, colID, keys, timeout)
+}